@@ -0,0 +1,120 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/alebsys/telegram-article-bot/internal/backup"
+	"github.com/alebsys/telegram-article-bot/internal/store"
+)
+
+// runCommand runs a subcommand with its arguments.
+func runCommand(name string, args []string) error {
+	switch name {
+	case "backup":
+		return backupCommand(args)
+	case "restore":
+		return restoreCommand(args)
+	}
+	return fmt.Errorf("unknown command %q, use backup or restore", name)
+}
+
+// backupCommand writes all data of the store at STORE_PATH to a file.
+func backupCommand(args []string) error {
+	fs := flag.NewFlagSet("backup", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: STORE_PATH=<store> telegram-article-bot backup <file>")
+	}
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	s, err := openStore(true)
+	if err != nil {
+		return err
+	}
+
+	a, err := backup.Create(s, time.Now())
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(fs.Arg(0), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+	if err = a.Write(f); err != nil {
+		f.Close()
+		return err
+	}
+	if err = f.Close(); err != nil {
+		return err
+	}
+	printContents(a)
+	return nil
+}
+
+// restoreCommand replaces all data of the store at STORE_PATH with a backup.
+func restoreCommand(args []string) error {
+	fs := flag.NewFlagSet("restore", flag.ExitOnError)
+	force := fs.Bool("force", false, "replace data of a store which is not empty")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: STORE_PATH=<store> telegram-article-bot restore [-force] <file>")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	s, err := openStore(false)
+	if err != nil {
+		return err
+	}
+	empty, err := backup.Empty(s)
+	if err != nil {
+		return err
+	}
+	if !empty && !*force {
+		return fmt.Errorf("store %s is not empty, use -force to replace its data", os.Getenv("STORE_PATH"))
+	}
+
+	f, err := os.Open(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	a, err := backup.Read(f)
+	if err != nil {
+		return err
+	}
+	if err = a.Restore(s); err != nil {
+		return err
+	}
+	printContents(a)
+	return nil
+}
+
+// openStore opens the store at STORE_PATH, which subcommands require. A missing store file is an error
+// if the file must exist, e.g. to back it up, otherwise it is a new empty store.
+func openStore(mustExist bool) (*store.JSONStore, error) {
+	path := os.Getenv("STORE_PATH")
+	if path == "" {
+		return nil, fmt.Errorf("STORE_PATH is not set")
+	}
+	if mustExist {
+		if _, err := os.Stat(path); err != nil {
+			return nil, fmt.Errorf("error when opens store: %v", err)
+		}
+	}
+	return store.Open(path)
+}
+
+func printContents(a *backup.Archive) {
+	for _, b := range a.Contents() {
+		log.Printf("%s: %d keys", b.Name, b.Keys)
+	}
+}
@@ -1,71 +1,285 @@
 package main
 
 import (
+	"fmt"
 	"log"
+	"net/http"
+	"net/url"
 	"os"
+	"strconv"
+	"sync"
+	"time"
+	_ "time/tzdata"
 
+	"github.com/alebsys/telegram-article-bot/internal/archive"
+	"github.com/alebsys/telegram-article-bot/internal/bot"
+	"github.com/alebsys/telegram-article-bot/internal/cache"
 	"github.com/alebsys/telegram-article-bot/internal/devto"
+	"github.com/alebsys/telegram-article-bot/internal/host"
+	"github.com/alebsys/telegram-article-bot/internal/proxy"
+	"github.com/alebsys/telegram-article-bot/internal/ratelimit"
+	"github.com/alebsys/telegram-article-bot/internal/secret"
+	"github.com/alebsys/telegram-article-bot/internal/store"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
 
 const (
-	descp = "`Request example::\n/article go 10 5\nгде:\n* go - topic (tag);\n* 10 - search period in days;\n* 5 - number of posts.`"
+	// webTimeout limits fetching of a page of link.
+	webTimeout        = 10 * time.Second
+	defaultSourceHost = "dev.to"
 )
 
 func main() {
-	bot, err := tgbotapi.NewBotAPI(os.Getenv("TELEGRAM_APITOKEN"))
+	if len(os.Args) > 1 {
+		if err := runCommand(os.Args[1], os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		log.Panic("loading config: ", err)
+	}
+
+	s, err := store.Open(os.Getenv("STORE_PATH"))
 	if err != nil {
-		log.Panic("getting TELEGRAM_APITOKEN: ", err)
+		log.Panic("opening STORE_PATH: ", err)
 	}
-	bot.Debug = false
 
-	log.Printf("Authorized on account %s", bot.Self.UserName)
+	var opts []bot.Option
+	if days := os.Getenv("HISTORY_RETENTION_DAYS"); days != "" {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			log.Panic("parsing HISTORY_RETENTION_DAYS: ", err)
+		}
+		opts = append(opts, bot.WithRetention(time.Duration(n)*24*time.Hour))
+	}
+
+	if limit := os.Getenv("HISTORY_LIMIT"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil {
+			log.Panic("parsing HISTORY_LIMIT: ", err)
+		}
+		opts = append(opts, bot.WithHistoryLimit(n))
+	}
+
+	keys, err := loadKeys(os.Getenv("SECRET_KEY_FILE"), os.Getenv("SECRET_KEY"))
+	if err != nil {
+		log.Panic("loading secret keys: ", err)
+	}
+	var keyring *secret.Keyring
+	if len(keys) > 0 {
+		if keyring, err = secret.NewKeyring(keys...); err != nil {
+			log.Panic("loading secret keys: ", err)
+		}
+	}
+
+	h := &hosted{
+		store:    s,
+		cache:    cache.New(time.Duration(cfg.CacheSeconds)*time.Second, 0),
+		limiters: make(map[string]*ratelimit.Limiter),
+		archives: make(map[string]*archive.Archive),
+		keyring:  keyring,
+		config:   cfg,
+		opts:     opts,
+		redactor: &host.Redactor{},
+	}
+	if err = tgbotapi.SetLogger(h.redactor); err != nil {
+		log.Panic("setting logger: ", err)
+	}
 
-	u := tgbotapi.NewUpdate(0)
-	u.Timeout = 60
-	updates := bot.GetUpdatesChan(u)
+	var wg sync.WaitGroup
+	for _, b := range cfg.Bots {
+		b := b
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			host.Supervise(b.Name, func() error { return h.run(b) })
+		}()
+	}
+	wg.Wait()
+}
+
+// loadConfig reads config of bots from BOTS_CONFIG or makes config of one bot with token from TELEGRAM_APITOKEN.
+func loadConfig() (*host.Config, error) {
+	if path := os.Getenv("BOTS_CONFIG"); path != "" {
+		return host.LoadConfig(path)
+	}
+	cfg, err := host.NewConfig(host.BotConfig{Name: "bot", TokenEnv: "TELEGRAM_APITOKEN"})
+	if err != nil {
+		return nil, err
+	}
+	noProxy := proxy.ParseNoProxy(os.Getenv("NO_PROXY"))
+	cfg.Proxies = host.Proxies{
+		Telegram: proxy.Config{URL: os.Getenv("TELEGRAM_PROXY"), NoProxy: noProxy},
+		Devto:    proxy.Config{URL: os.Getenv("DEVTO_PROXY"), NoProxy: noProxy},
+		Web:      proxy.Config{URL: os.Getenv("WEB_PROXY"), NoProxy: noProxy},
+	}
+	return cfg, nil
+}
+
+// hosted is what bots of the process share.
+type hosted struct {
+	store    store.Store
+	cache    *cache.Cache
+	mu       sync.Mutex
+	limiters map[string]*ratelimit.Limiter
+	// archives are kept by DEV.TO host, so a bot reading a fake API for staging doesn't mix its articles into the others
+	archives map[string]*archive.Archive
+	keyring  *secret.Keyring
+	config   *host.Config
+	opts     []bot.Option
+	redactor *host.Redactor
+}
+
+// run starts bot and handles its updates until it fails.
+func (h *hosted) run(cfg host.BotConfig) error {
+	token, err := cfg.TokenValue()
+	if err != nil {
+		return err
+	}
+	h.redactor.Add(token.Reveal())
+
+	proxies := h.config.ProxiesOf(cfg)
+	for _, p := range []proxy.Config{proxies.Telegram, proxies.Devto, proxies.Web} {
+		h.redactor.Add(p.Password())
+	}
+	telegram, err := proxies.Telegram.Client()
+	if err != nil {
+		return fmt.Errorf("telegram proxy: %v", err)
+	}
+	api, err := tgbotapi.NewBotAPIWithClient(token.Reveal(), tgbotapi.APIEndpoint, telegram)
+	if err != nil {
+		return fmt.Errorf("%s", h.redactor.Redact(err.Error()))
+	}
+	api.Debug = false
+	log.Printf("Bot %s authorized on account %s", cfg.Name, api.Self.UserName)
 
-	for update := range updates {
-		if update.EditedMessage != nil {
-			continue
+	s := store.WithPrefix(h.store, cfg.Namespace)
+	opts := append([]bot.Option{}, h.opts...)
+	opts = append(opts, bot.WithAccess(bot.Access{Users: cfg.AllowUsers, Chats: cfg.AllowChats}))
+	client, err := h.devto(cfg.DevtoURL, proxies.Devto)
+	if err != nil {
+		return err
+	}
+	a, err := h.archive(cfg.DevtoURL)
+	if err != nil {
+		return err
+	}
+	opts = append(opts, bot.WithArchive(a))
+	web, err := proxies.Web.Transport()
+	if err != nil {
+		return fmt.Errorf("web proxy: %v", err)
+	}
+	opts = append(opts, bot.WithDevto(client), bot.WithWebClient(&http.Client{Timeout: webTimeout, Transport: web}))
+	if cfg.HistoryLimit > 0 {
+		opts = append(opts, bot.WithHistoryLimit(cfg.HistoryLimit))
+	}
+	if cfg.HistoryRetentionDays != nil {
+		opts = append(opts, bot.WithRetention(time.Duration(*cfg.HistoryRetentionDays)*24*time.Hour))
+	}
+	if h.keyring != nil {
+		vault := secret.NewVault(s, h.keyring)
+		n, err := vault.Rotate()
+		if err != nil {
+			return fmt.Errorf("error when rotates secret keys: %v", err)
 		}
-		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "")
-		msg.ParseMode = "markdown"
-		msg.DisableWebPagePreview = true
-
-		input := update.Message.Text
-
-		log.Printf("[%s] %s", update.Message.From.UserName, input)
-
-		switch update.Message.Command() {
-		case "help":
-			msg.Text = "`Hello! I can find articles of interest to you on DEV.TO\n\n`" + descp
-		case "article":
-			note := "`Enter the correct command!\n\n`" + descp
-
-			b := devto.ValidateInput(input)
-			if !b {
-				msg.Text = note
-				break
-			}
-
-			query, err := devto.ParseInput(input)
-			if err != nil {
-				log.Print(err)
-				continue
-			}
-			articles, err := devto.GetArticles(query.Tag, query.Freshness)
-			if err != nil {
-				log.Print(err)
-				continue
-			}
-
-			msg.Text = articles.WriteArticles(query.Limit)
-		default:
-			msg.Text = "`I don't know this command. Enter /help`"
+		if n > 0 {
+			log.Printf("Bot %s re-encrypted %d secrets with key %s", cfg.Name, n, h.keyring.Primary())
 		}
+		opts = append(opts, bot.WithVault(vault))
+	}
 
-		bot.Send(msg)
+	bot.New(api, secret.WithoutSecrets(s), opts...).Run()
+	return nil
+}
+
+// devto returns client of DEV.TO API at baseURL which shares the cache and the rate limiter of the host with other bots.
+func (h *hosted) devto(baseURL string, p proxy.Config) (*devto.Client, error) {
+	transport, err := p.Transport()
+	if err != nil {
+		return nil, fmt.Errorf("devto proxy: %v", err)
+	}
+	var opts []devto.ClientOption
+	apiHost, err := sourceHost(baseURL)
+	if err != nil {
+		return nil, err
 	}
+	if baseURL != "" {
+		opts = append(opts, devto.WithBaseURL(baseURL))
+	}
+
+	h.mu.Lock()
+	limiter, ok := h.limiters[apiHost]
+	if !ok {
+		limiter = ratelimit.New(h.config.DevtoRate, h.config.DevtoBurst)
+		h.limiters[apiHost] = limiter
+	}
+	h.mu.Unlock()
 
+	opts = append(opts, devto.WithTransport(&cache.Transport{
+		Cache: h.cache,
+		Next:  &ratelimit.Transport{Limiter: limiter, Next: transport},
+	}))
+	return devto.NewClient(opts...), nil
+}
+
+// archive returns archive of articles read from DEV.TO API at baseURL which is shared by bots reading the same host.
+// Articles of DEV.TO itself are kept in the store as is, those of other hosts under the host name.
+func (h *hosted) archive(baseURL string) (*archive.Archive, error) {
+	apiHost, err := sourceHost(baseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	a, ok := h.archives[apiHost]
+	if !ok {
+		s := h.store
+		if apiHost != defaultSourceHost {
+			// '@' is not allowed in namespaces of bots, so the prefix never clashes with them
+			s = store.WithPrefix(h.store, "@"+apiHost)
+		}
+		a = archive.New(s)
+		h.archives[apiHost] = a
+	}
+	return a, nil
+}
+
+// sourceHost returns host of DEV.TO API at baseURL, empty URL is DEV.TO itself.
+func sourceHost(baseURL string) (string, error) {
+	if baseURL == "" {
+		return defaultSourceHost, nil
+	}
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return "", fmt.Errorf("error when parses DEV.TO URL: %v", err)
+	}
+	return u.Host, nil
+}
+
+// loadKeys reads master keys from the key file followed by the key from the environment.
+// The first key is the primary one.
+func loadKeys(path, envKey string) ([]secret.Key, error) {
+	var keys []secret.Key
+	if path != "" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		if keys, err = secret.ParseKeyFile(f); err != nil {
+			return nil, err
+		}
+	}
+	if envKey != "" {
+		k, err := secret.ParseKey(envKey)
+		if err != nil {
+			return nil, fmt.Errorf("SECRET_KEY: %v", err)
+		}
+		keys = append(keys, secret.Key{ID: secret.EnvKeyID, Bytes: k})
+	}
+	return keys, nil
 }
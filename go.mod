@@ -2,4 +2,10 @@ module github.com/alebsys/telegram-article-bot
 
 go 1.17
 
-require github.com/go-telegram-bot-api/telegram-bot-api/v5 v5.5.1
+require (
+	github.com/alecthomas/chroma v0.10.0
+	github.com/go-telegram-bot-api/telegram-bot-api/v5 v5.5.1
+	github.com/yuin/goldmark v1.4.12
+)
+
+require github.com/dlclark/regexp2 v1.4.0 // indirect
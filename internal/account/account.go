@@ -0,0 +1,98 @@
+package account
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/alebsys/telegram-article-bot/internal/secret"
+	"github.com/alebsys/telegram-article-bot/internal/store"
+)
+
+const bucket = "accounts"
+
+// Account is a DEV.TO account linked by user with a personal API key.
+// The key is kept in secret.Vault.
+type Account struct {
+	Username string    `json:"username"`
+	LinkedAt time.Time `json:"linked_at"`
+}
+
+// Accounts keeps linked accounts of users.
+type Accounts struct {
+	mu    sync.Mutex
+	store store.Store
+	vault *secret.Vault
+}
+
+// New returns Accounts backed by s which keeps API keys in vault.
+func New(s store.Store, vault *secret.Vault) *Accounts {
+	return &Accounts{store: s, vault: vault}
+}
+
+// Link saves API key of user's DEV.TO account replacing the linked one.
+func (a *Accounts) Link(userID int64, username string, apiKey secret.Value, now time.Time) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if err := a.vault.Put(secretName(userID), apiKey); err != nil {
+		return err
+	}
+	if err := a.store.Put(bucket, key(userID), Account{Username: username, LinkedAt: now}); err != nil {
+		return fmt.Errorf("error when saves account of %d: %v", userID, err)
+	}
+	return nil
+}
+
+// Get returns account linked by user.
+func (a *Accounts) Get(userID int64) (Account, bool, error) {
+	var acc Account
+	ok, err := a.store.Get(bucket, key(userID), &acc)
+	if err != nil {
+		return acc, false, fmt.Errorf("error when reads account of %d: %v", userID, err)
+	}
+	return acc, ok, nil
+}
+
+// Key returns API key of user.
+func (a *Accounts) Key(userID int64) (secret.Value, bool, error) {
+	if _, ok, err := a.Get(userID); err != nil || !ok {
+		return secret.Value{}, false, err
+	}
+	return a.vault.Get(secretName(userID))
+}
+
+// Unlink removes account of user with its key.
+func (a *Accounts) Unlink(userID int64) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if err := a.vault.Delete(secretName(userID)); err != nil {
+		return err
+	}
+	return a.store.Delete(bucket, key(userID))
+}
+
+// Export returns linked account of user. The key is not exported.
+func (a *Accounts) Export(userID int64) (interface{}, error) {
+	acc, ok, err := a.Get(userID)
+	if err != nil || !ok {
+		return nil, err
+	}
+	return map[string]interface{}{"username": acc.Username, "linked_at": acc.LinkedAt}, nil
+}
+
+// Forget removes account of user.
+func (a *Accounts) Forget(userID int64) error {
+	return a.Unlink(userID)
+}
+
+// secretName is the name of API key of user in the vault.
+func secretName(userID int64) string {
+	return bucket + ":" + key(userID)
+}
+
+func key(userID int64) string {
+	return strconv.FormatInt(userID, 10)
+}
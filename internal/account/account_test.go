@@ -0,0 +1,36 @@
+package account
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/alebsys/telegram-article-bot/internal/secret"
+	"github.com/alebsys/telegram-article-bot/internal/store"
+)
+
+func TestAccounts(t *testing.T) {
+	keys, _ := secret.NewKeyring(secret.Key{ID: "test", Bytes: bytes.Repeat([]byte{1}, secret.KeySize)})
+	s := store.NewMemory()
+	a := New(s, secret.NewVault(s, keys))
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	if err := a.Link(1, "ben", secret.NewValue("s3cr3t"), now); err != nil {
+		t.Fatalf("Link: got error %v", err)
+	}
+	acc, ok, err := a.Get(1)
+	if err != nil || !ok || acc.Username != "ben" {
+		t.Errorf("Get: got %+v, %v, %v; want account of ben", acc, ok, err)
+	}
+	apiKey, ok, err := a.Key(1)
+	if err != nil || !ok || apiKey.Reveal() != "s3cr3t" {
+		t.Errorf("Key: got %v, %v; want s3cr3t", ok, err)
+	}
+
+	if err = a.Unlink(1); err != nil {
+		t.Fatalf("Unlink: got error %v", err)
+	}
+	if _, ok, _ = a.Key(1); ok {
+		t.Errorf("Key: after Unlink; got key")
+	}
+}
@@ -0,0 +1,191 @@
+package archive
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/alebsys/telegram-article-bot/internal/devto"
+	"github.com/alebsys/telegram-article-bot/internal/store"
+)
+
+const (
+	bucket = "archive"
+	// daysBucket indexes archived articles by the day they were published, so reading recent articles
+	// and purging old ones don't decode the whole archive.
+	daysBucket = "archive_days"
+	dayLayout  = "2006-01-02"
+)
+
+// Archive is a local copy of articles fetched from DEV.TO.
+type Archive struct {
+	mu    sync.Mutex
+	store store.Store
+}
+
+// New returns Archive backed by s.
+func New(s store.Store) *Archive {
+	return &Archive{store: s}
+}
+
+// Put saves articles to archive replacing older copies, all of them at once.
+func (a *Archive) Put(articles ...devto.Article) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	days := make(map[string][]int)
+	// day returns ids of articles published on the day, reading them from the store once
+	day := func(d string) ([]int, error) {
+		ids, ok := days[d]
+		if !ok {
+			if _, err := a.store.Get(daysBucket, d, &ids); err != nil {
+				return nil, err
+			}
+		}
+		return ids, nil
+	}
+
+	b := &store.Batch{}
+	for _, article := range articles {
+		if article.ID == 0 {
+			continue
+		}
+		var old devto.Article
+		found, err := a.store.Get(bucket, key(article.ID), &old)
+		if err != nil {
+			return fmt.Errorf("error when archives articles: %v", err)
+		}
+		d := dayOf(article.PublishedAt)
+		if oldDay := dayOf(old.PublishedAt); found && oldDay != d {
+			ids, err := day(oldDay)
+			if err != nil {
+				return fmt.Errorf("error when archives articles: %v", err)
+			}
+			days[oldDay] = remove(ids, article.ID)
+		}
+		ids, err := day(d)
+		if err != nil {
+			return fmt.Errorf("error when archives articles: %v", err)
+		}
+		days[d] = append(remove(ids, article.ID), article.ID)
+		b.Put(bucket, key(article.ID), article)
+	}
+	for d, ids := range days {
+		if len(ids) == 0 {
+			b.Delete(daysBucket, d)
+		} else {
+			b.Put(daysBucket, d, ids)
+		}
+	}
+	if err := store.Apply(a.store, b); err != nil {
+		return fmt.Errorf("error when archives articles: %v", err)
+	}
+	return nil
+}
+
+// Get returns archived article by id.
+func (a *Archive) Get(id int) (devto.Article, bool, error) {
+	var article devto.Article
+	ok, err := a.store.Get(bucket, key(id), &article)
+	return article, ok, err
+}
+
+// Since returns archived articles published at or after t. Only articles of the days since t are decoded.
+func (a *Archive) Since(t time.Time) (devto.Articles, error) {
+	days, err := a.store.Keys(daysBucket)
+	if err != nil {
+		return nil, err
+	}
+	// keys are sorted and days in the layout sort as dates
+	from := dayOf(t)
+	i := sort.SearchStrings(days, from)
+
+	var articles devto.Articles
+	for _, d := range days[i:] {
+		var ids []int
+		if _, err := a.store.Get(daysBucket, d, &ids); err != nil {
+			return nil, err
+		}
+		for _, id := range ids {
+			article, ok, err := a.Get(id)
+			if err != nil {
+				return nil, err
+			}
+			if ok && !article.PublishedAt.Before(t) {
+				articles = append(articles, article)
+			}
+		}
+	}
+	return articles, nil
+}
+
+// Sample returns up to n archived articles accepted by keep. Articles are decoded one by one
+// in random order until n of them are found, so a sample does not cost reading the whole archive.
+func (a *Archive) Sample(r *rand.Rand, n int, keep func(devto.Article) bool) (devto.Articles, error) {
+	keys, err := a.store.Keys(bucket)
+	if err != nil {
+		return nil, err
+	}
+	var articles devto.Articles
+	for _, i := range r.Perm(len(keys)) {
+		if len(articles) == n {
+			break
+		}
+		var article devto.Article
+		if _, err := a.store.Get(bucket, keys[i], &article); err != nil {
+			return nil, err
+		}
+		if keep(article) {
+			articles = append(articles, article)
+		}
+	}
+	return articles, nil
+}
+
+// Purge removes articles published before the day of t with their samples.
+// Whole days are dropped through the index without decoding their articles.
+func (a *Archive) Purge(t time.Time) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	days, err := a.store.Keys(daysBucket)
+	if err != nil {
+		return err
+	}
+	b := &store.Batch{}
+	for _, d := range days[:sort.SearchStrings(days, dayOf(t))] {
+		var ids []int
+		if _, err := a.store.Get(daysBucket, d, &ids); err != nil {
+			return err
+		}
+		for _, id := range ids {
+			b.Delete(bucket, key(id))
+			b.Delete(samplesBucket, key(id))
+		}
+		b.Delete(daysBucket, d)
+	}
+	return store.Apply(a.store, b)
+}
+
+// dayOf returns the day of t in UTC as a key of the index.
+func dayOf(t time.Time) string {
+	return t.UTC().Format(dayLayout)
+}
+
+// remove returns ids without id.
+func remove(ids []int, id int) []int {
+	kept := ids[:0]
+	for _, i := range ids {
+		if i != id {
+			kept = append(kept, i)
+		}
+	}
+	return kept
+}
+
+func key(id int) string {
+	return strconv.Itoa(id)
+}
@@ -0,0 +1,315 @@
+package archive
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/alebsys/telegram-article-bot/internal/devto"
+	"github.com/alebsys/telegram-article-bot/internal/store"
+)
+
+func TestSince(t *testing.T) {
+	now := time.Now()
+	a := New(store.NewMemory())
+	a.Put(
+		devto.Article{ID: 1, PublishedAt: now.Add(-48 * time.Hour)},
+		devto.Article{ID: 2, PublishedAt: now.Add(-time.Hour)},
+		devto.Article{Title: "without id is skipped", PublishedAt: now},
+	)
+
+	articles, err := a.Since(now.Add(-24 * time.Hour))
+	if err != nil {
+		t.Fatalf("Since: got error %v", err)
+	}
+	if len(articles) != 1 || articles[0].ID != 2 {
+		t.Errorf("Since: got %v; want article 2", articles)
+	}
+
+	if err = a.Purge(now.Add(-24 * time.Hour)); err != nil {
+		t.Fatalf("Purge: got error %v", err)
+	}
+	if _, ok, _ := a.Get(1); ok {
+		t.Errorf("Purge: got article 1 published before t")
+	}
+	if _, ok, _ := a.Get(2); !ok {
+		t.Errorf("Purge: got no article 2 published after t")
+	}
+}
+
+func TestPutIndex(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 10, d, 12, 0, 0, 0, time.UTC) }
+	a := New(store.NewMemory())
+	a.Put(devto.Article{ID: 1, PublishedAt: day(10)}, devto.Article{ID: 2, PublishedAt: day(12)})
+	// updated copies stay once in the index, the one published at another day moves there
+	a.Put(devto.Article{ID: 1, PublishedAt: day(10), Score: 5}, devto.Article{ID: 2, PublishedAt: day(14)})
+
+	cases := []struct {
+		name  string
+		since time.Time
+		want  []int
+	}{
+		{"all", day(1), []int{1, 2}},
+		{"moved article", day(13), []int{2}},
+		{"none", day(15), nil},
+	}
+	for _, c := range cases {
+		articles, err := a.Since(c.since)
+		if err != nil {
+			t.Fatalf("Since: %s; got error %v", c.name, err)
+		}
+		var ids []int
+		for _, article := range articles {
+			ids = append(ids, article.ID)
+		}
+		if !reflect.DeepEqual(ids, c.want) {
+			t.Errorf("Since: %s; got %v; want %v", c.name, ids, c.want)
+		}
+	}
+
+	if err := a.Purge(day(13)); err != nil {
+		t.Fatalf("Purge: got error %v", err)
+	}
+	if _, ok, _ := a.Get(2); !ok {
+		t.Errorf("Purge: got no article 2 moved after t")
+	}
+	if _, ok, _ := a.Get(1); ok {
+		t.Errorf("Purge: got article 1 published before t")
+	}
+}
+
+func TestSample(t *testing.T) {
+	a := New(store.NewMemory())
+	for id := 1; id <= 10; id++ {
+		a.Put(devto.Article{ID: id, Score: id})
+	}
+	even := func(article devto.Article) bool { return article.Score%2 == 0 }
+	r := rand.New(rand.NewSource(1))
+
+	cases := []struct {
+		name string
+		n    int
+		want int
+	}{
+		{"fewer than accepted", 3, 3},
+		{"all accepted", 5, 5},
+		{"more than accepted", 8, 5},
+	}
+	for _, c := range cases {
+		articles, err := a.Sample(r, c.n, even)
+		if err != nil {
+			t.Fatalf("Sample: %s; got error %v", c.name, err)
+		}
+		if len(articles) != c.want {
+			t.Errorf("Sample: %s; got %d articles; want %d", c.name, len(articles), c.want)
+		}
+		seen := make(map[int]bool)
+		for _, article := range articles {
+			if !even(article) || seen[article.ID] {
+				t.Errorf("Sample: %s; got %v", c.name, articles)
+				break
+			}
+			seen[article.ID] = true
+		}
+	}
+}
+
+func TestTrends(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	article := func(age time.Duration, score int, tags ...string) devto.Article {
+		return devto.Article{PublishedAt: now.Add(-age), Score: score, Tags: tags}
+	}
+	articles := devto.Articles{
+		// go is growing: 3 articles this week, 1 a week before
+		article(day, 10, "go"),
+		article(2*day, 20, "go"),
+		article(3*day, 30, "go", "rust"),
+		article(8*day, 5, "go"),
+		// rust is steady: 1 article every week
+		article(9*day, 1, "rust"),
+		article(15*day, 1, "rust"),
+		// too old for the baseline
+		article(40*day, 100, "go"),
+		// zig is new but has a single article with few reactions
+		article(day, 5, "zig"),
+	}
+
+	trends := Trends(articles, now, 2, 1)
+	if len(trends) != 3 {
+		t.Fatalf("Trends: got %d tags; want 3", len(trends))
+	}
+	goTrend := trends[0]
+	if goTrend.Tag != "go" || goTrend.Count != 3 || goTrend.PrevCount != 1 || goTrend.Reactions != 60 || goTrend.PrevReactions != 5 {
+		t.Errorf("Trends: got %+v; want go with 3 articles and 60 reactions", goTrend)
+	}
+	if goTrend.Baseline != 0.5 || goTrend.ReactionBaseline != 2.5 || goTrend.Velocity != ((3-0.5)/1.5+(60-2.5)/3.5)/2 {
+		t.Errorf("Trends: got baselines %v, %v and velocity %v", goTrend.Baseline, goTrend.ReactionBaseline, goTrend.Velocity)
+	}
+	if trends[1].Tag != "rust" || trends[2].Tag != "zig" {
+		t.Errorf("Trends: got order %s, %s, %s; want go, rust, zig", trends[0].Tag, trends[1].Tag, trends[2].Tag)
+	}
+
+	if got := Trends(articles, now, 2, 2); len(got) != 1 {
+		t.Errorf("Trends: min count 2; got %d tags; want 1", len(got))
+	}
+}
+
+func TestDelta(t *testing.T) {
+	if d, ok := Delta(15, 10); !ok || d != 0.5 {
+		t.Errorf("Delta: got %v, %v; want 0.5", d, ok)
+	}
+	if _, ok := Delta(15, 0); ok {
+		t.Errorf("Delta: nothing to compare with; got ok")
+	}
+}
+
+func TestDaily(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	articles := devto.Articles{
+		{Tags: devto.Tags{"go"}, Score: 10, PublishedAt: now.Add(-time.Hour)},
+		{Tags: devto.Tags{"go", "rust"}, Score: 20, PublishedAt: now.Add(-2 * time.Hour)},
+		{Tags: devto.Tags{"go"}, Score: 6, PublishedAt: now.Add(-24 * time.Hour)},
+		{Tags: devto.Tags{"rust"}, Score: 100, PublishedAt: now.Add(-time.Hour)},
+		{Tags: devto.Tags{"go"}, Score: 100, PublishedAt: now.Add(-72 * time.Hour)},
+	}
+
+	days := Daily(articles, "go", now, 3)
+	cases := []struct {
+		count int
+		avg   float64
+	}{{0, 0}, {1, 6}, {2, 15}}
+	if len(days) != len(cases) {
+		t.Fatalf("Daily: got %d days; want %d", len(days), len(cases))
+	}
+	for i, c := range cases {
+		if days[i].Count != c.count || days[i].AvgReactions() != c.avg {
+			t.Errorf("Daily: day %d; got %d, %v; want %d, %v", i, days[i].Count, days[i].AvgReactions(), c.count, c.avg)
+		}
+	}
+	if want := time.Date(2026, 10, 14, 0, 0, 0, 0, time.UTC); !days[0].Date.Equal(want) {
+		t.Errorf("Daily: first day %v; want %v", days[0].Date, want)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	articles := devto.Articles{
+		{ID: 1, Tags: devto.Tags{"go"}, Score: 10, User: devto.User{Username: "ann"}, PublishedAt: now},
+		{ID: 2, Tags: devto.Tags{"go"}, Score: 3, User: devto.User{Username: "bob"}, PublishedAt: now},
+		{ID: 3, Tags: devto.Tags{"go"}, Score: 8, User: devto.User{Username: "bob"}, PublishedAt: now},
+		{ID: 4, Tags: devto.Tags{"go"}, Score: 1, User: devto.User{Username: "bob"}, PublishedAt: now},
+		{ID: 5, Tags: devto.Tags{"go"}, Score: 500, User: devto.User{Username: "old"}, PublishedAt: now.AddDate(0, -2, 0)},
+		{ID: 6, Tags: devto.Tags{"rust"}, Score: 50, User: devto.User{Username: "eve"}, PublishedAt: now},
+	}
+
+	s := Summarize(articles, "go", now.AddDate(0, 0, -30))
+	if s.Count != 4 || s.MedianReactions != 5.5 || s.TopAuthor != "bob" || s.TopArticle.ID != 1 {
+		t.Errorf("Summarize: got %d, %v, %s, %d; want 4, 5.5, bob, 1", s.Count, s.MedianReactions, s.TopAuthor, s.TopArticle.ID)
+	}
+	if s := Summarize(articles, "zig", now); s.Count != 0 || s.TopAuthor != "" {
+		t.Errorf("Summarize: no articles; got %+v", s)
+	}
+}
+
+func TestLeaders(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	ann := devto.User{Username: "ann"}
+	bob := devto.User{Username: "bob"}
+	articles := devto.Articles{
+		{Tags: devto.Tags{"go"}, Score: 10, User: ann, PublishedAt: now},
+		{Tags: devto.Tags{"go"}, Score: 3, User: bob, PublishedAt: now},
+		{Tags: devto.Tags{"go"}, Score: 8, User: bob, PublishedAt: now},
+		{Tags: devto.Tags{"go"}, Score: 1, User: devto.User{Username: "eve"}, PublishedAt: now},
+		{Tags: devto.Tags{"go"}, Score: 500, User: ann, PublishedAt: now.AddDate(0, -2, 0)},
+		{Tags: devto.Tags{"rust"}, Score: 50, User: ann, PublishedAt: now},
+	}
+
+	got := Leaders(articles, "go", now.AddDate(0, 0, -30), 2)
+	want := []Leader{{Username: "bob", Articles: 2, Reactions: 11}, {Username: "ann", Articles: 1, Reactions: 10}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Leaders: got %+v; want %+v", got, want)
+	}
+}
+
+func TestGrowth(t *testing.T) {
+	now := time.Date(2022, 5, 2, 12, 0, 0, 0, time.UTC)
+	from := now.Add(-24 * time.Hour)
+	samples := []Sample{
+		{Time: now.Add(-30 * time.Hour), Reactions: 10},
+		{Time: now.Add(-20 * time.Hour), Reactions: 25},
+		{Time: now.Add(-time.Hour), Reactions: 50},
+	}
+
+	cases := []struct {
+		name      string
+		samples   []Sample
+		published time.Time
+		want      int
+		ok        bool
+	}{
+		{"sampled before the day", samples, now.Add(-48 * time.Hour), 40, true},
+		{"published today", samples[1:], now.Add(-22 * time.Hour), 50, true},
+		{"sampled only today", samples[1:], now.Add(-48 * time.Hour), 25, true},
+		{"no samples", nil, now, 0, false},
+	}
+	for _, c := range cases {
+		got, ok := Growth(c.samples, c.published, from)
+		if got != c.want || ok != c.ok {
+			t.Errorf("Growth: %s; got %d, %v; want %d, %v", c.name, got, ok, c.want, c.ok)
+		}
+	}
+}
+
+func TestRecord(t *testing.T) {
+	now := time.Date(2022, 5, 2, 12, 0, 0, 0, time.UTC)
+	a := New(store.NewMemory())
+	published := now.Add(-72 * time.Hour)
+	for i, score := range []int{10, 10, 30, 45} {
+		article := devto.Article{ID: 1, Score: score, PublishedAt: published}
+		if err := a.Record(devto.Articles{article}, now.Add(time.Duration(i-3)*12*time.Hour)); err != nil {
+			t.Fatalf("Record: got error %v", err)
+		}
+	}
+	samples, _ := a.Samples(1)
+	if len(samples) != 3 {
+		t.Errorf("Record: got %d samples; want 3 as unchanged counts are skipped", len(samples))
+	}
+
+	rising, err := a.Rising(devto.Articles{{ID: 1, PublishedAt: published}, {ID: 2, PublishedAt: published}}, now, 1)
+	if err != nil {
+		t.Fatalf("Rising: got error %v", err)
+	}
+	if len(rising) != 1 || rising[0].ID != 1 || rising[0].Growth != 35 {
+		t.Errorf("Rising: got %+v; want article 1 with growth 35", rising)
+	}
+
+	if err = a.PurgeSamples(now.Add(-time.Hour)); err != nil {
+		t.Fatalf("PurgeSamples: got error %v", err)
+	}
+	samples, _ = a.Samples(1)
+	if len(samples) != 2 || samples[0].Reactions != 30 {
+		t.Errorf("PurgeSamples: got %+v; want the last old sample and the new one", samples)
+	}
+	if err = a.PurgeSamples(now.Add(time.Hour)); err != nil {
+		t.Fatalf("PurgeSamples: got error %v", err)
+	}
+	if samples, _ = a.Samples(1); len(samples) != 1 || samples[0].Reactions != 45 {
+		t.Errorf("PurgeSamples: got %+v; want the last sample of a quiet article as its baseline", samples)
+	}
+	if err = a.Record(devto.Articles{{ID: 1, Score: 50, PublishedAt: published}}, now.Add(2*time.Hour)); err != nil {
+		t.Fatalf("Record: got error %v", err)
+	}
+	if rising, _ = a.Rising(devto.Articles{{ID: 1, PublishedAt: published}}, now.Add(2*time.Hour), 1); len(rising) != 1 || rising[0].Growth != 5 {
+		t.Errorf("Rising: got %+v; want growth 5 over the kept baseline", rising)
+	}
+
+	a.Put(devto.Article{ID: 1, PublishedAt: published})
+	if err = a.Purge(now); err != nil {
+		t.Fatalf("Purge: got error %v", err)
+	}
+	if samples, _ = a.Samples(1); len(samples) != 0 {
+		t.Errorf("Purge: got %+v; want no samples of a purged article", samples)
+	}
+}
@@ -0,0 +1,96 @@
+package archive
+
+import (
+	"sort"
+	"time"
+
+	"github.com/alebsys/telegram-article-bot/internal/devto"
+)
+
+// Summary is how a tag did during a period.
+type Summary struct {
+	Tag             string
+	Count           int
+	MedianReactions float64
+	// TopAuthor is the username of the author who got the most reactions with the tag.
+	TopAuthor  string
+	TopArticle devto.Article
+}
+
+// Summarize returns summary of articles with tag published at or after from.
+func Summarize(articles devto.Articles, tag string, from time.Time) Summary {
+	s := Summary{Tag: tag}
+	var reactions []int
+	authors := make(map[string]int)
+	for _, a := range articles {
+		if !a.Tags.Has(tag) || a.PublishedAt.Before(from) {
+			continue
+		}
+		s.Count++
+		reactions = append(reactions, a.Score)
+		authors[a.User.Username] += a.Score
+		if s.Count == 1 || a.Score > s.TopArticle.Score {
+			s.TopArticle = a
+		}
+	}
+	if len(reactions) == 0 {
+		return s
+	}
+
+	sort.Ints(reactions)
+	if n := len(reactions); n%2 == 1 {
+		s.MedianReactions = float64(reactions[n/2])
+	} else {
+		s.MedianReactions = float64(reactions[n/2-1]+reactions[n/2]) / 2
+	}
+	best := -1
+	for author, r := range authors {
+		if author == "" {
+			continue
+		}
+		if r > best || r == best && author < s.TopAuthor {
+			s.TopAuthor, best = author, r
+		}
+	}
+	return s
+}
+
+// Leader is an author ranked by reactions to articles with a tag.
+type Leader struct {
+	Username  string
+	Name      string
+	Articles  int
+	Reactions int
+}
+
+// Leaders returns up to n authors whose articles with tag published at or after from got the most reactions.
+func Leaders(articles devto.Articles, tag string, from time.Time, n int) []Leader {
+	leaders := make(map[string]*Leader)
+	for _, a := range articles {
+		if !a.Tags.Has(tag) || a.PublishedAt.Before(from) || a.User.Username == "" {
+			continue
+		}
+		l, ok := leaders[a.User.Username]
+		if !ok {
+			l = &Leader{Username: a.User.Username, Name: a.User.Name}
+			leaders[a.User.Username] = l
+		}
+		l.Articles++
+		l.Reactions += a.Score
+	}
+
+	result := make([]Leader, 0, len(leaders))
+	for _, l := range leaders {
+		result = append(result, *l)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Reactions != result[j].Reactions {
+			return result[i].Reactions > result[j].Reactions
+		}
+		return result[i].Username < result[j].Username
+	})
+	if len(result) > n {
+		result = result[:n]
+	}
+	return result
+}
@@ -0,0 +1,132 @@
+package archive
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/alebsys/telegram-article-bot/internal/devto"
+	"github.com/alebsys/telegram-article-bot/internal/store"
+)
+
+const samplesBucket = "samples"
+
+// Sample is the number of reactions and comments of an article at a time.
+type Sample struct {
+	Time      time.Time `json:"t"`
+	Reactions int       `json:"r"`
+	Comments  int       `json:"c"`
+}
+
+// Record adds samples of the current counts of articles taken at now.
+// A sample is skipped if the counts have not changed since the last one,
+// so the last sample stays valid until a new one is taken. All samples are saved at once.
+func (a *Archive) Record(articles devto.Articles, now time.Time) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	b := &store.Batch{}
+	for _, article := range articles {
+		if article.ID == 0 {
+			continue
+		}
+		samples, err := a.Samples(article.ID)
+		if err != nil {
+			return err
+		}
+		if n := len(samples); n > 0 && samples[n-1].Reactions == article.Score && samples[n-1].Comments == article.Comments {
+			continue
+		}
+		samples = append(samples, Sample{Time: now, Reactions: article.Score, Comments: article.Comments})
+		b.Put(samplesBucket, key(article.ID), samples)
+	}
+	if err := store.Apply(a.store, b); err != nil {
+		return fmt.Errorf("error when records samples: %v", err)
+	}
+	return nil
+}
+
+// Samples returns samples of article, the oldest first.
+func (a *Archive) Samples(id int) ([]Sample, error) {
+	var samples []Sample
+	_, err := a.store.Get(samplesBucket, key(id), &samples)
+	return samples, err
+}
+
+// PurgeSamples removes samples taken before t except the last one of every article,
+// which is the baseline of later growth. An article whose counts have not changed since
+// keeps its last sample, samples are removed with the article by Purge.
+func (a *Archive) PurgeSamples(t time.Time) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	keys, err := a.store.Keys(samplesBucket)
+	if err != nil {
+		return err
+	}
+	b := &store.Batch{}
+	for _, k := range keys {
+		var samples []Sample
+		if _, err = a.store.Get(samplesBucket, k, &samples); err != nil {
+			return err
+		}
+		n := len(samples)
+		if n == 0 {
+			b.Delete(samplesBucket, k)
+			continue
+		}
+		if i := sort.Search(n, func(i int) bool { return !samples[i].Time.Before(t) }); i > 1 {
+			b.Put(samplesBucket, k, samples[i-1:])
+		}
+	}
+	return store.Apply(a.store, b)
+}
+
+// SetGrowth sets Growth of articles to reactions they got during the day before now.
+func (a *Archive) SetGrowth(articles devto.Articles, now time.Time) error {
+	for i := range articles {
+		samples, err := a.Samples(articles[i].ID)
+		if err != nil {
+			return err
+		}
+		articles[i].Growth, _ = Growth(samples, articles[i].PublishedAt, now.Add(-24*time.Hour))
+	}
+	return nil
+}
+
+// Growth returns how many reactions an article published at published got since from,
+// judging by samples. Counts at from are those of the last sample taken before it, or zero
+// if the article was published after from. Otherwise growth is counted since the first sample.
+func Growth(samples []Sample, published, from time.Time) (int, bool) {
+	n := len(samples)
+	if n == 0 {
+		return 0, false
+	}
+	var base int
+	switch i := sort.Search(n, func(i int) bool { return samples[i].Time.After(from) }); {
+	case i > 0:
+		base = samples[i-1].Reactions
+	case published.After(from):
+		base = 0
+	default:
+		base = samples[0].Reactions
+	}
+	return samples[n-1].Reactions - base, true
+}
+
+// Rising returns articles which got at least min reactions during the day before now, the fastest growing first.
+func (a *Archive) Rising(articles devto.Articles, now time.Time, min int) (devto.Articles, error) {
+	if err := a.SetGrowth(articles, now); err != nil {
+		return nil, err
+	}
+	var rising devto.Articles
+	for _, article := range articles {
+		if article.Growth >= min {
+			rising = append(rising, article)
+		}
+	}
+	sort.SliceStable(rising, func(i, j int) bool {
+		return rising[i].Growth > rising[j].Growth
+	})
+	return rising, nil
+}
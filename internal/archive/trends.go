@@ -0,0 +1,129 @@
+package archive
+
+import (
+	"sort"
+	"time"
+
+	"github.com/alebsys/telegram-article-bot/internal/devto"
+)
+
+const week = 7 * 24 * time.Hour
+
+// Trend is how a tag does this week compared with the previous weeks.
+type Trend struct {
+	Tag           string
+	Count         int
+	PrevCount     int
+	Reactions     int
+	PrevReactions int
+	// Baseline is the average weekly number of articles over the baseline weeks before this one.
+	Baseline float64
+	// ReactionBaseline is the average weekly number of reactions to articles of the baseline weeks.
+	ReactionBaseline float64
+	// Velocity is the mean of relative growth of articles and of their reactions over the baselines.
+	Velocity float64
+}
+
+// Trends compares articles of every tag published during the week before now with the previous
+// baselineWeeks and returns tags having at least minCount articles this week, the fastest growing first.
+func Trends(articles devto.Articles, now time.Time, baselineWeeks, minCount int) []Trend {
+	trends := make(map[string]*Trend)
+	baseline := make(map[string]int)
+	reactionBaseline := make(map[string]int)
+	from := now.Add(-time.Duration(baselineWeeks+1) * week)
+
+	for _, a := range articles {
+		if a.PublishedAt.Before(from) || !a.PublishedAt.Before(now) {
+			continue
+		}
+		n := int(now.Sub(a.PublishedAt) / week) // 0 is this week, 1 is the previous one
+		for _, tag := range a.Tags {
+			t, ok := trends[tag]
+			if !ok {
+				t = &Trend{Tag: tag}
+				trends[tag] = t
+			}
+			switch n {
+			case 0:
+				t.Count++
+				t.Reactions += a.Score
+			case 1:
+				t.PrevCount++
+				t.PrevReactions += a.Score
+			}
+			if n > 0 {
+				baseline[tag]++
+				reactionBaseline[tag] += a.Score
+			}
+		}
+	}
+
+	var result []Trend
+	for tag, t := range trends {
+		if t.Count < minCount {
+			continue
+		}
+		if baselineWeeks > 0 {
+			t.Baseline = float64(baseline[tag]) / float64(baselineWeeks)
+			t.ReactionBaseline = float64(reactionBaseline[tag]) / float64(baselineWeeks)
+		}
+		count := (float64(t.Count) - t.Baseline) / (t.Baseline + 1)
+		reactions := (float64(t.Reactions) - t.ReactionBaseline) / (t.ReactionBaseline + 1)
+		t.Velocity = (count + reactions) / 2
+		result = append(result, *t)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Velocity != result[j].Velocity {
+			return result[i].Velocity > result[j].Velocity
+		}
+		return result[i].Tag < result[j].Tag
+	})
+	return result
+}
+
+// Delta returns relative change from prev to cur, ok is false if there is nothing to compare with.
+func Delta(cur, prev int) (delta float64, ok bool) {
+	if prev == 0 {
+		return 0, false
+	}
+	return float64(cur-prev) / float64(prev), true
+}
+
+// Day is what was published with a tag during one day.
+type Day struct {
+	Date      time.Time
+	Count     int
+	Reactions int
+}
+
+// AvgReactions returns the average number of reactions per article of the day.
+func (d Day) AvgReactions() float64 {
+	if d.Count == 0 {
+		return 0
+	}
+	return float64(d.Reactions) / float64(d.Count)
+}
+
+// Daily splits articles with tag published during the days before now by UTC day, the oldest day first.
+func Daily(articles devto.Articles, tag string, now time.Time, days int) []Day {
+	now = now.UTC()
+	last := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	from := last.AddDate(0, 0, -(days - 1))
+
+	result := make([]Day, days)
+	for i := range result {
+		result[i].Date = from.AddDate(0, 0, i)
+	}
+	for _, a := range articles {
+		if !a.Tags.Has(tag) || a.PublishedAt.Before(from) || a.PublishedAt.After(now) {
+			continue
+		}
+		i := int(a.PublishedAt.Sub(from) / (24 * time.Hour))
+		if i >= days {
+			continue
+		}
+		result[i].Count++
+		result[i].Reactions += a.Score
+	}
+	return result
+}
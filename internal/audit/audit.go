@@ -0,0 +1,170 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/alebsys/telegram-article-bot/internal/store"
+)
+
+const bucket = "audit"
+
+// Entry is a record of an administrative action.
+type Entry struct {
+	Seq     int64           `json:"seq"`
+	Time    time.Time       `json:"time"`
+	ActorID int64           `json:"actor_id"`
+	Actor   string          `json:"actor,omitempty"`
+	ChatID  int64           `json:"chat_id"`
+	Action  string          `json:"action"`
+	Before  json.RawMessage `json:"before,omitempty"`
+	After   json.RawMessage `json:"after,omitempty"`
+}
+
+// Log is an append-only log of administrative actions. Entries are never changed or deleted,
+// they are kept for as long as the store.
+type Log struct {
+	mu    sync.Mutex
+	store store.Store
+	seq   int64
+}
+
+// New returns Log backed by s.
+func New(s store.Store) *Log {
+	return &Log{store: s}
+}
+
+// Append records e with before and after values of what has changed, nil values are omitted.
+func (l *Log) Append(e Entry, before, after interface{}) error {
+	var err error
+	if e.Before, err = marshal(before); err != nil {
+		return err
+	}
+	if e.After, err = marshal(after); err != nil {
+		return err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.seq == 0 {
+		keys, err := l.store.Keys(bucket)
+		if err != nil {
+			return err
+		}
+		if n := len(keys); n > 0 {
+			if l.seq, err = strconv.ParseInt(keys[n-1], 10, 64); err != nil {
+				return fmt.Errorf("error when parses audit key %q: %v", keys[n-1], err)
+			}
+		}
+	}
+	e.Seq = l.seq + 1
+	if err = l.store.Put(bucket, key(e.Seq), e); err != nil {
+		return err
+	}
+	l.seq = e.Seq
+	return nil
+}
+
+// Query selects entries, zero values match everything.
+type Query struct {
+	ChatID  int64
+	ActorID int64
+	Action  string
+	Since   time.Time
+}
+
+func (q Query) match(e Entry) bool {
+	return (q.ChatID == 0 || e.ChatID == q.ChatID) &&
+		(q.ActorID == 0 || e.ActorID == q.ActorID) &&
+		(q.Action == "" || e.Action == q.Action) &&
+		!e.Time.Before(q.Since)
+}
+
+// List returns at most limit last entries matching q, newest first. Zero limit returns all of them.
+func (l *Log) List(q Query, limit int) ([]Entry, error) {
+	var entries []Entry
+	err := l.each(true, func(e Entry) bool {
+		if q.match(e) {
+			entries = append(entries, e)
+		}
+		return limit == 0 || len(entries) < limit
+	})
+	return entries, err
+}
+
+// WriteJSONL writes entries matching q to w as JSON lines, oldest first.
+func (l *Log) WriteJSONL(w io.Writer, q Query) error {
+	enc := json.NewEncoder(w)
+	var err error
+	eachErr := l.each(false, func(e Entry) bool {
+		if q.match(e) {
+			err = enc.Encode(e)
+		}
+		return err == nil
+	})
+	if eachErr != nil {
+		return eachErr
+	}
+	if err != nil {
+		return fmt.Errorf("error when writes audit log: %v", err)
+	}
+	return nil
+}
+
+// Export returns actions made by user.
+func (l *Log) Export(userID int64) (interface{}, error) {
+	entries, err := l.List(Query{ActorID: userID}, 0)
+	if err != nil || len(entries) == 0 {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// Forget keeps actions made by user: otherwise an administrator could erase their own actions from the log.
+func (l *Log) Forget(userID int64) error {
+	return nil
+}
+
+// each calls fn for entries in order of keys until fn returns false.
+func (l *Log) each(newestFirst bool, fn func(Entry) bool) error {
+	keys, err := l.store.Keys(bucket)
+	if err != nil {
+		return err
+	}
+	for i := range keys {
+		k := keys[i]
+		if newestFirst {
+			k = keys[len(keys)-1-i]
+		}
+		var e Entry
+		ok, err := l.store.Get(bucket, k, &e)
+		if err != nil {
+			return err
+		}
+		if ok && !fn(e) {
+			return nil
+		}
+	}
+	return nil
+}
+
+func marshal(v interface{}) (json.RawMessage, error) {
+	if v == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("error when marshal audit value: %v", err)
+	}
+	return data, nil
+}
+
+// key pads seq with zeros, so keys are sorted in order of entries.
+func key(seq int64) string {
+	return fmt.Sprintf("%020d", seq)
+}
@@ -0,0 +1,89 @@
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/alebsys/telegram-article-bot/internal/store"
+)
+
+func TestLog(t *testing.T) {
+	s := store.NewMemory()
+	l := New(s)
+	now := time.Date(2022, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	entries := []struct {
+		e      Entry
+		before interface{}
+		after  interface{}
+	}{
+		{Entry{Time: now, ActorID: 1, Actor: "ann", ChatID: -100, Action: "subscribe"}, nil, []string{"go"}},
+		{Entry{Time: now.Add(time.Hour), ActorID: 2, Actor: "bob", ChatID: -100, Action: "set"}, "on", "off"},
+		{Entry{Time: now.Add(2 * time.Hour), ActorID: 1, Actor: "ann", ChatID: 1, Action: "subscribe"}, []string{"go"}, []string{"rust"}},
+	}
+	for _, c := range entries {
+		if err := l.Append(c.e, c.before, c.after); err != nil {
+			t.Fatalf("Append: got error %v", err)
+		}
+	}
+
+	cases := []struct {
+		name  string
+		q     Query
+		limit int
+		want  []int64
+	}{
+		{"all", Query{}, 0, []int64{3, 2, 1}},
+		{"limit", Query{}, 1, []int64{3}},
+		{"chat", Query{ChatID: -100}, 0, []int64{2, 1}},
+		{"action", Query{Action: "subscribe"}, 0, []int64{3, 1}},
+		{"since", Query{Since: now.Add(time.Hour)}, 0, []int64{3, 2}},
+	}
+	for _, c := range cases {
+		got, err := l.List(c.q, c.limit)
+		if err != nil {
+			t.Fatalf("List: %s; got error %v", c.name, err)
+		}
+		var seqs []int64
+		for _, e := range got {
+			seqs = append(seqs, e.Seq)
+		}
+		if !reflect.DeepEqual(seqs, c.want) {
+			t.Errorf("List: %s; got %v; want %v", c.name, seqs, c.want)
+		}
+	}
+
+	// a new log continues the sequence of the stored one
+	l = New(s)
+	if err := l.Append(Entry{Time: now, ActorID: 2, ChatID: -100, Action: "filter"}, nil, nil); err != nil {
+		t.Fatalf("Append: got error %v", err)
+	}
+	buf := new(bytes.Buffer)
+	if err := l.WriteJSONL(buf, Query{ChatID: -100}); err != nil {
+		t.Fatalf("WriteJSONL: got error %v", err)
+	}
+	var seqs []int64
+	scanner := bufio.NewScanner(buf)
+	for scanner.Scan() {
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			t.Fatalf("WriteJSONL: got bad line %q: %v", scanner.Text(), err)
+		}
+		seqs = append(seqs, e.Seq)
+	}
+	if !reflect.DeepEqual(seqs, []int64{1, 2, 4}) {
+		t.Errorf("WriteJSONL: got entries %v; want [1 2 4]", seqs)
+	}
+
+	if err := l.Forget(1); err != nil {
+		t.Fatalf("Forget: got error %v", err)
+	}
+	got, _ := l.List(Query{ActorID: 1}, 0)
+	if len(got) != 2 || got[0].Actor != "ann" {
+		t.Errorf("Forget: got %+v; want actions of the user kept", got)
+	}
+}
@@ -0,0 +1,142 @@
+package backup
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"time"
+
+	"github.com/alebsys/telegram-article-bot/internal/store"
+)
+
+const (
+	// Version is the version of archives made by this bot.
+	Version = 2
+	// firstVersion is the first version of archives with metadata. Version 1 is a plain copy of the store file.
+	firstVersion = 2
+)
+
+// migrations upgrade an archive of the version to the next one.
+var migrations = map[int]func(*Archive) error{
+	// a copy of the store file has the same buckets as an archive of version 2
+	1: func(a *Archive) error { return nil },
+}
+
+// Archive is a snapshot of all data of a store.
+type Archive struct {
+	Version   int                                   `json:"version"`
+	CreatedAt time.Time                             `json:"created_at"`
+	Buckets   map[string]map[string]json.RawMessage `json:"buckets"`
+}
+
+// Bucket is a number of keys in a bucket.
+type Bucket struct {
+	Name string
+	Keys int
+}
+
+// Contents returns buckets of the archive sorted by name.
+func (a *Archive) Contents() []Bucket {
+	contents := make([]Bucket, 0, len(a.Buckets))
+	for name, b := range a.Buckets {
+		contents = append(contents, Bucket{Name: name, Keys: len(b)})
+	}
+	sort.Slice(contents, func(i, j int) bool {
+		return contents[i].Name < contents[j].Name
+	})
+	return contents
+}
+
+// Create makes archive of all data of s.
+func Create(s store.Snapshotter, now time.Time) (*Archive, error) {
+	buckets, err := s.Snapshot()
+	if err != nil {
+		return nil, fmt.Errorf("error when snapshots store: %v", err)
+	}
+	return &Archive{Version: Version, CreatedAt: now.UTC(), Buckets: buckets}, nil
+}
+
+// Write writes gzipped JSON of archive to w.
+func (a *Archive) Write(w io.Writer) error {
+	zw := gzip.NewWriter(w)
+	if err := json.NewEncoder(zw).Encode(a); err != nil {
+		return fmt.Errorf("error when writes backup: %v", err)
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("error when writes backup: %v", err)
+	}
+	return nil
+}
+
+// Read reads archive from r, gzipped or not, and migrates it to the current version.
+// A copy of the store file is read as an archive of version 1.
+func Read(r io.Reader) (*Archive, error) {
+	br := bufio.NewReader(r)
+	if magic, err := br.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		zr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, fmt.Errorf("error when reads backup: %v", err)
+		}
+		defer zr.Close()
+		r = zr
+	} else {
+		r = br
+	}
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("error when reads backup: %v", err)
+	}
+
+	var fields map[string]json.RawMessage
+	if err = json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("error when unmarshal backup: %v", err)
+	}
+	a := new(Archive)
+	// buckets of a store file are objects, so a number means an archive
+	if v, ok := fields["version"]; ok && json.Unmarshal(v, &a.Version) == nil {
+		if err = json.Unmarshal(data, a); err != nil {
+			return nil, fmt.Errorf("error when unmarshal backup: %v", err)
+		}
+		if a.Version < firstVersion {
+			return nil, fmt.Errorf("backup version %d is not supported, archives start with version %d", a.Version, firstVersion)
+		}
+	} else {
+		a.Version = 1
+		if err = json.Unmarshal(data, &a.Buckets); err != nil {
+			return nil, fmt.Errorf("error when unmarshal store file: %v", err)
+		}
+	}
+
+	if a.Version > Version {
+		return nil, fmt.Errorf("backup version %d is not supported, this bot reads versions up to %d", a.Version, Version)
+	}
+	for a.Version < Version {
+		migrate, ok := migrations[a.Version]
+		if !ok {
+			return nil, fmt.Errorf("backup version %d has no migration to version %d", a.Version, a.Version+1)
+		}
+		if err = migrate(a); err != nil {
+			return nil, fmt.Errorf("error when migrates backup from version %d: %v", a.Version, err)
+		}
+		a.Version++
+	}
+	return a, nil
+}
+
+// Restore replaces all data of s with the archive.
+func (a *Archive) Restore(s store.Snapshotter) error {
+	if err := s.Restore(a.Buckets); err != nil {
+		return fmt.Errorf("error when restores store: %v", err)
+	}
+	return nil
+}
+
+// Empty reports whether s has no data.
+func Empty(s store.Snapshotter) (bool, error) {
+	buckets, err := s.Snapshot()
+	return len(buckets) == 0, err
+}
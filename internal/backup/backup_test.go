@@ -0,0 +1,88 @@
+package backup
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alebsys/telegram-article-bot/internal/store"
+)
+
+func TestBackup(t *testing.T) {
+	s := store.NewMemory()
+	s.Put("subscriptions", "-100", map[string]interface{}{"tags": []string{"go"}})
+	s.Put("team/settings", "1", map[string]string{"unfurl": "on"})
+	now := time.Date(2022, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	a, err := Create(s, now)
+	if err != nil {
+		t.Fatalf("Create: got error %v", err)
+	}
+	buf := new(bytes.Buffer)
+	if err = a.Write(buf); err != nil {
+		t.Fatalf("Write: got error %v", err)
+	}
+	read, err := Read(buf)
+	if err != nil {
+		t.Fatalf("Read: got error %v", err)
+	}
+	if read.Version != Version || !read.CreatedAt.Equal(now) {
+		t.Errorf("Read: got version %d created at %v; want %d at %v", read.Version, read.CreatedAt, Version, now)
+	}
+	want := []Bucket{{"subscriptions", 1}, {"team/settings", 1}}
+	if got := read.Contents(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Contents: got %v; want %v", got, want)
+	}
+
+	restored := store.NewMemory()
+	restored.Put("old", "1", 1)
+	if err = read.Restore(restored); err != nil {
+		t.Fatalf("Restore: got error %v", err)
+	}
+	original, _ := s.Snapshot()
+	got, _ := restored.Snapshot()
+	if !reflect.DeepEqual(got, original) {
+		t.Errorf("Restore: got %s; want %s", got, original)
+	}
+}
+
+func TestRead(t *testing.T) {
+	cases := []struct {
+		name    string
+		data    string
+		buckets int
+		err     bool
+	}{
+		{"store file", `{"subscriptions":{"-100":{"tags":["go"]}},"version":{"1":2}}`, 2, false},
+		{"plain archive", `{"version":2,"created_at":"2022-05-01T12:00:00Z","buckets":{"a":{"1":1}}}`, 1, false},
+		{"newer version", `{"version":3,"buckets":{}}`, 0, true},
+		{"archive of version 1", `{"version":1,"buckets":{}}`, 0, true},
+		{"not JSON", `backup`, 0, true},
+	}
+	for _, c := range cases {
+		a, err := Read(strings.NewReader(c.data))
+		if (err != nil) != c.err {
+			t.Errorf("Read: %s; got error %v; want error %v", c.name, err, c.err)
+			continue
+		}
+		if err == nil && (a.Version != Version || len(a.Buckets) != c.buckets) {
+			t.Errorf("Read: %s; got version %d with %d buckets; want %d with %d", c.name, a.Version, len(a.Buckets), Version, c.buckets)
+		}
+	}
+
+	// the migrated store file keeps values as they are
+	a, _ := Read(strings.NewReader(`{"a":{"1":{"x":1}}}`))
+	if !reflect.DeepEqual(a.Buckets["a"]["1"], json.RawMessage(`{"x":1}`)) {
+		t.Errorf("Read: store file; got value %s", a.Buckets["a"]["1"])
+	}
+
+	migrate := migrations[1]
+	delete(migrations, 1)
+	defer func() { migrations[1] = migrate }()
+	if _, err := Read(strings.NewReader(`{"a":{"1":1}}`)); err == nil {
+		t.Errorf("Read: store file without migration; got no error")
+	}
+}
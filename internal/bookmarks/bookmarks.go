@@ -0,0 +1,300 @@
+package bookmarks
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alebsys/telegram-article-bot/internal/link"
+	"github.com/alebsys/telegram-article-bot/internal/store"
+)
+
+const (
+	bucket         = "bookmarks"
+	messagesBucket = "bookmark_messages"
+	// lastIDBucket keeps the last bookmark id of every user, so ids of deleted bookmarks are never reused
+	lastIDBucket = "bookmark_last_id"
+
+	StateUnread = "unread"
+	StateRead   = "read"
+)
+
+var hashtagRgxp = regexp.MustCompile(`#([\p{L}\p{N}_]+)`)
+
+// ReminderStages is how long after saving an unread bookmark is resurfaced.
+var ReminderStages = []time.Duration{24 * time.Hour, 7 * 24 * time.Hour, 30 * 24 * time.Hour}
+
+// Bookmark is a link saved by user.
+type Bookmark struct {
+	ID int `json:"id"`
+	link.Link
+	State        string    `json:"state"`
+	SavedAt      time.Time `json:"saved_at"`
+	Reminded     int       `json:"reminded,omitempty"`
+	SnoozedUntil time.Time `json:"snoozed_until,omitempty"`
+	Note         string    `json:"note,omitempty"`
+	PersonalTags []string  `json:"personal_tags,omitempty"`
+}
+
+// AddNote appends text to the note and adds hashtags found in text to personal tags.
+// Text made of hashtags only does not change the note.
+func (bm *Bookmark) AddNote(text string) {
+	for _, match := range hashtagRgxp.FindAllStringSubmatch(text, -1) {
+		if tag := strings.ToLower(match[1]); !bm.HasTag(tag) {
+			bm.PersonalTags = append(bm.PersonalTags, tag)
+		}
+	}
+
+	if strings.TrimSpace(hashtagRgxp.ReplaceAllString(text, "")) == "" {
+		return
+	}
+	if bm.Note != "" {
+		bm.Note += "\n"
+	}
+	bm.Note += strings.TrimSpace(text)
+}
+
+// HasTag reports whether bookmark has personal tag.
+func (bm *Bookmark) HasTag(tag string) bool {
+	for _, t := range bm.PersonalTags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// Search returns bookmarks having all tags and containing text in title, description or note, ignoring case.
+func Search(list []Bookmark, tags []string, text string) []Bookmark {
+	text = strings.ToLower(text)
+	var found []Bookmark
+	for _, bm := range list {
+		matched := true
+		for _, tag := range tags {
+			if !bm.HasTag(strings.ToLower(strings.TrimPrefix(tag, "#"))) {
+				matched = false
+				break
+			}
+		}
+		if matched && text != "" {
+			matched = strings.Contains(strings.ToLower(bm.Title), text) ||
+				strings.Contains(strings.ToLower(bm.Description), text) ||
+				strings.Contains(strings.ToLower(bm.Note), text)
+		}
+		if matched {
+			found = append(found, bm)
+		}
+	}
+	return found
+}
+
+// ReminderDue reports whether unread bookmark has to be resurfaced at now.
+func (bm *Bookmark) ReminderDue(now time.Time) bool {
+	if bm.State != StateUnread || bm.Reminded >= len(ReminderStages) || now.Before(bm.SnoozedUntil) {
+		return false
+	}
+	return !now.Before(bm.SavedAt.Add(ReminderStages[bm.Reminded]))
+}
+
+// Snooze postpones the last reminder of bookmark until the given time: the reminder is sent again then
+// instead of waiting for the next stage.
+func (bm *Bookmark) Snooze(until time.Time) {
+	if bm.Reminded > 0 {
+		bm.Reminded--
+	}
+	bm.SnoozedUntil = until
+}
+
+// Bookmarks keeps links saved by users.
+type Bookmarks struct {
+	mu    sync.Mutex
+	store store.Store
+}
+
+// New returns Bookmarks backed by s.
+func New(s store.Store) *Bookmarks {
+	return &Bookmarks{store: s}
+}
+
+// Add saves link for user. It returns the existing bookmark and false if the URL is already saved.
+func (b *Bookmarks) Add(userID int64, l link.Link, now time.Time) (Bookmark, bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	list, err := b.list(userID)
+	if err != nil {
+		return Bookmark{}, false, err
+	}
+	var last int
+	if _, err = b.store.Get(lastIDBucket, key(userID), &last); err != nil {
+		return Bookmark{}, false, err
+	}
+	for _, bm := range list {
+		if bm.URL == l.URL {
+			return bm, false, nil
+		}
+		if bm.ID > last {
+			last = bm.ID
+		}
+	}
+
+	bm := Bookmark{ID: last + 1, Link: l, State: StateUnread, SavedAt: now}
+	batch := &store.Batch{}
+	batch.Put(bucket, key(userID), append(list, bm))
+	batch.Put(lastIDBucket, key(userID), bm.ID)
+	return bm, true, store.Apply(b.store, batch)
+}
+
+// List returns bookmarks of user from the oldest to the newest.
+func (b *Bookmarks) List(userID int64) ([]Bookmark, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.list(userID)
+}
+
+// Get returns bookmark of user by id.
+func (b *Bookmarks) Get(userID int64, id int) (Bookmark, bool, error) {
+	list, err := b.List(userID)
+	if err != nil {
+		return Bookmark{}, false, err
+	}
+	for _, bm := range list {
+		if bm.ID == id {
+			return bm, true, nil
+		}
+	}
+	return Bookmark{}, false, nil
+}
+
+// Update changes bookmark of user by id with fn and reports false if there is no such bookmark.
+func (b *Bookmarks) Update(userID int64, id int, fn func(bm *Bookmark)) (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	list, err := b.list(userID)
+	if err != nil {
+		return false, err
+	}
+	for i := range list {
+		if list[i].ID == id {
+			fn(&list[i])
+			return true, b.store.Put(bucket, key(userID), list)
+		}
+	}
+	return false, nil
+}
+
+// Delete removes bookmark of user by id with the records of messages showing it.
+func (b *Bookmarks) Delete(userID int64, id int) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	list, err := b.list(userID)
+	if err != nil {
+		return err
+	}
+	kept := list[:0]
+	for _, bm := range list {
+		if bm.ID != id {
+			kept = append(kept, bm)
+		}
+	}
+
+	batch := &store.Batch{}
+	if len(kept) == 0 {
+		batch.Delete(bucket, key(userID))
+	} else {
+		batch.Put(bucket, key(userID), kept)
+	}
+	keys, err := b.store.Keys(messagesBucket)
+	if err != nil {
+		return err
+	}
+	for _, k := range keys {
+		if !strings.HasPrefix(k, key(userID)+":") {
+			continue
+		}
+		var shown int
+		if _, err = b.store.Get(messagesBucket, k, &shown); err != nil {
+			return err
+		}
+		if shown == id {
+			batch.Delete(messagesBucket, k)
+		}
+	}
+	return store.Apply(b.store, batch)
+}
+
+// Remember records that message in chat of user shows bookmark id, so replies to it can be attached to the bookmark.
+func (b *Bookmarks) Remember(userID, chatID int64, messageID, id int) error {
+	return b.store.Put(messagesBucket, messageKey(userID, chatID, messageID), id)
+}
+
+// ByMessage returns bookmark id shown in message of chat to user.
+func (b *Bookmarks) ByMessage(userID, chatID int64, messageID int) (int, bool, error) {
+	var id int
+	ok, err := b.store.Get(messagesBucket, messageKey(userID, chatID, messageID), &id)
+	return id, ok, err
+}
+
+// Users returns ids of users who have bookmarks.
+func (b *Bookmarks) Users() ([]int64, error) {
+	keys, err := b.store.Keys(bucket)
+	if err != nil {
+		return nil, err
+	}
+	users := make([]int64, 0, len(keys))
+	for _, k := range keys {
+		if id, err := strconv.ParseInt(k, 10, 64); err == nil {
+			users = append(users, id)
+		}
+	}
+	return users, nil
+}
+
+// Export returns bookmarks of user.
+func (b *Bookmarks) Export(userID int64) (interface{}, error) {
+	return b.List(userID)
+}
+
+// Forget removes bookmarks of user.
+func (b *Bookmarks) Forget(userID int64) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	keys, err := b.store.Keys(messagesBucket)
+	if err != nil {
+		return err
+	}
+	for _, k := range keys {
+		if strings.HasPrefix(k, key(userID)+":") {
+			if err = b.store.Delete(messagesBucket, k); err != nil {
+				return err
+			}
+		}
+	}
+	if err = b.store.Delete(lastIDBucket, key(userID)); err != nil {
+		return err
+	}
+	return b.store.Delete(bucket, key(userID))
+}
+
+func (b *Bookmarks) list(userID int64) ([]Bookmark, error) {
+	var list []Bookmark
+	if _, err := b.store.Get(bucket, key(userID), &list); err != nil {
+		return nil, fmt.Errorf("error when reads bookmarks of %d: %v", userID, err)
+	}
+	return list, nil
+}
+
+func key(userID int64) string {
+	return strconv.FormatInt(userID, 10)
+}
+
+func messageKey(userID, chatID int64, messageID int) string {
+	return fmt.Sprintf("%d:%d:%d", userID, chatID, messageID)
+}
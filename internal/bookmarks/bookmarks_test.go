@@ -0,0 +1,155 @@
+package bookmarks
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/alebsys/telegram-article-bot/internal/link"
+	"github.com/alebsys/telegram-article-bot/internal/store"
+)
+
+func TestBookmarks(t *testing.T) {
+	now := time.Now()
+	b := New(store.NewMemory())
+
+	first, added, err := b.Add(1, link.Link{URL: "https://a"}, now)
+	if err != nil || !added || first.ID != 1 || first.State != StateUnread {
+		t.Fatalf("Add: got %+v, %v, %v", first, added, err)
+	}
+	second, _, _ := b.Add(1, link.Link{URL: "https://b"}, now)
+	if second.ID != 2 {
+		t.Errorf("Add: got id %d; want 2", second.ID)
+	}
+	if dup, added, _ := b.Add(1, link.Link{URL: "https://a"}, now); added || dup.ID != 1 {
+		t.Errorf("Add: duplicate URL; got %+v, %v", dup, added)
+	}
+
+	ok, err := b.Update(1, 2, func(bm *Bookmark) { bm.State = StateRead })
+	if err != nil || !ok {
+		t.Fatalf("Update: got %v, %v", ok, err)
+	}
+	if bm, _, _ := b.Get(1, 2); bm.State != StateRead {
+		t.Errorf("Update: got state %q; want %q", bm.State, StateRead)
+	}
+	if ok, _ = b.Update(1, 3, func(*Bookmark) {}); ok {
+		t.Errorf("Update: missing bookmark updated")
+	}
+
+	b.Remember(1, 1, 100, 1)
+	b.Remember(1, 1, 101, 2)
+	b.Delete(1, 1)
+	if _, ok, _ := b.ByMessage(1, 1, 100); ok {
+		t.Errorf("Delete: got message of the deleted bookmark")
+	}
+	if id, ok, _ := b.ByMessage(1, 1, 101); !ok || id != 2 {
+		t.Errorf("Delete: got message of bookmark %d, %v; want 2 kept", id, ok)
+	}
+	third, _, _ := b.Add(1, link.Link{URL: "https://c"}, now)
+	if third.ID != 3 {
+		t.Errorf("Add: after Delete; got id %d; want 3", third.ID)
+	}
+	b.Delete(1, 3)
+	if fourth, _, _ := b.Add(1, link.Link{URL: "https://d"}, now); fourth.ID != 4 {
+		t.Errorf("Add: after Delete of the newest; got id %d; want 4 as ids are never reused", fourth.ID)
+	}
+	if list, _ := b.List(1); len(list) != 2 {
+		t.Errorf("List: got %d bookmarks; want 2", len(list))
+	}
+	if list, _ := b.List(2); len(list) != 0 {
+		t.Errorf("List: other user; got %v", list)
+	}
+}
+
+func TestReminderDue(t *testing.T) {
+	saved := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	cases := []struct {
+		name string
+		bm   Bookmark
+		now  time.Time
+		want bool
+	}{
+		{"too early", Bookmark{State: StateUnread, SavedAt: saved}, saved.Add(time.Hour), false},
+		{"first stage", Bookmark{State: StateUnread, SavedAt: saved}, saved.Add(day), true},
+		{"second stage too early", Bookmark{State: StateUnread, SavedAt: saved, Reminded: 1}, saved.Add(6 * day), false},
+		{"second stage", Bookmark{State: StateUnread, SavedAt: saved, Reminded: 1}, saved.Add(7 * day), true},
+		{"all stages done", Bookmark{State: StateUnread, SavedAt: saved, Reminded: 3}, saved.Add(100 * day), false},
+		{"read", Bookmark{State: StateRead, SavedAt: saved}, saved.Add(2 * day), false},
+		{"snoozed", Bookmark{State: StateUnread, SavedAt: saved, SnoozedUntil: saved.Add(3 * day)}, saved.Add(2 * day), false},
+	}
+	for _, c := range cases {
+		if got := c.bm.ReminderDue(c.now); got != c.want {
+			t.Errorf("ReminderDue: %s; got %v; want %v", c.name, got, c.want)
+		}
+	}
+}
+
+func TestSnooze(t *testing.T) {
+	saved := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	for stage := range ReminderStages {
+		bm := Bookmark{State: StateUnread, SavedAt: saved, Reminded: stage}
+		now := saved.Add(ReminderStages[stage])
+		if !bm.ReminderDue(now) {
+			t.Fatalf("ReminderDue: stage %d; got false; want true", stage)
+		}
+		bm.Reminded++
+
+		bm.Snooze(now.Add(day))
+		if bm.ReminderDue(now.Add(day - time.Minute)) {
+			t.Errorf("ReminderDue: stage %d snoozed; got true before 24h", stage)
+		}
+		if !bm.ReminderDue(now.Add(day)) {
+			t.Errorf("ReminderDue: stage %d snoozed; got false after 24h; want true", stage)
+		}
+	}
+}
+
+func TestAddNote(t *testing.T) {
+	bm := Bookmark{}
+	bm.AddNote("#Review #go")
+	bm.AddNote("great context on #review")
+	bm.AddNote("second note")
+
+	if bm.Note != "great context on #review\nsecond note" {
+		t.Errorf("AddNote: got note %q", bm.Note)
+	}
+	if len(bm.PersonalTags) != 2 || bm.PersonalTags[0] != "review" || bm.PersonalTags[1] != "go" {
+		t.Errorf("AddNote: got tags %v; want [review go]", bm.PersonalTags)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	list := []Bookmark{
+		{ID: 1, Link: link.Link{Title: "Context in Go"}, PersonalTags: []string{"go"}},
+		{ID: 2, Link: link.Link{Title: "Rust"}, Note: "compare with context package", PersonalTags: []string{"review", "go"}},
+		{ID: 3, Link: link.Link{Title: "Zig"}, PersonalTags: []string{"review"}},
+	}
+
+	cases := []struct {
+		name string
+		tags []string
+		text string
+		want []int
+	}{
+		{"everything", nil, "", []int{1, 2, 3}},
+		{"by tag", []string{"#review"}, "", []int{2, 3}},
+		{"by tags", []string{"#review", "#go"}, "", []int{2}},
+		{"by title and note", nil, "CONTEXT", []int{1, 2}},
+		{"by tag and text", []string{"#go"}, "package", []int{2}},
+		{"nothing", []string{"#missing"}, "", nil},
+	}
+	for _, c := range cases {
+		found := Search(list, c.tags, c.text)
+		var got []int
+		for _, bm := range found {
+			got = append(got, bm.ID)
+		}
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("Search: %s; got %v; want %v", c.name, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package bot
+
+import (
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Access lists users and chats allowed to use the bot. Empty lists allow everyone.
+type Access struct {
+	Users []int64
+	Chats []int64
+}
+
+// Allows reports whether user may use the bot in chat.
+func (a Access) Allows(chatID, userID int64) bool {
+	if len(a.Users) == 0 && len(a.Chats) == 0 {
+		return true
+	}
+	for _, id := range a.Users {
+		if id == userID {
+			return true
+		}
+	}
+	for _, id := range a.Chats {
+		if id == chatID {
+			return true
+		}
+	}
+	return false
+}
+
+// allowed reports whether the sender of message may use the bot and tells private chats if not.
+func (b *Bot) allowed(m *tgbotapi.Message) bool {
+	if b.access.Allows(m.Chat.ID, m.From.ID) {
+		return true
+	}
+	if m.Chat.IsPrivate() && m.IsCommand() {
+		b.send(newMessage(m.Chat.ID, "`Sorry, this bot is private`"))
+	}
+	return false
+}
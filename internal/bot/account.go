@@ -0,0 +1,180 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/alebsys/telegram-article-bot/internal/devto"
+	"github.com/alebsys/telegram-article-bot/internal/secret"
+	"github.com/alebsys/telegram-article-bot/internal/subscription"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	linkUsage         = "`Usage: /link <API key>\nGet the key at` https://dev.to/settings/extensions"
+	revokeHint        = "`You can also revoke the key at` https://dev.to/settings/extensions"
+	linkSeedTags      = 5
+	readingListShown  = 10
+	noAccountText     = "`Link your DEV.TO account first with /link`"
+	linkDisabledText  = "`Linking of DEV.TO accounts is disabled on this bot`"
+	revokedKeyText    = "`DEV.TO doesn't accept your API key anymore, link a new one with /link`"
+	secretCommandText = "/link ***"
+)
+
+// link links DEV.TO account of user with a personal API key and subscribes the user
+// to the followed tags unless the user has a subscription already.
+func (b *Bot) link(m *tgbotapi.Message) {
+	apiKey := secret.NewValue(strings.TrimSpace(m.CommandArguments()))
+	if !apiKey.IsZero() {
+		// the key must not stay in the chat history
+		if _, err := b.api.Request(tgbotapi.NewDeleteMessage(m.Chat.ID, m.MessageID)); err != nil {
+			log.Print(err)
+		}
+	}
+	if b.accounts == nil {
+		b.send(newMessage(m.Chat.ID, linkDisabledText))
+		return
+	}
+	if !m.Chat.IsPrivate() {
+		text := "`Link your account in our private chat`"
+		if !apiKey.IsZero() {
+			text = "`Never send your API key to a group! Revoke it and link a new one in our private chat:`\nhttps://dev.to/settings/extensions"
+		}
+		b.send(newMessage(m.Chat.ID, text))
+		return
+	}
+	if apiKey.IsZero() {
+		b.send(newMessage(m.Chat.ID, linkUsage))
+		return
+	}
+
+	me, err := b.devto.GetMe(apiKey.Reveal())
+	if errors.Is(err, devto.ErrUnauthorized) {
+		b.send(newMessage(m.Chat.ID, "`DEV.TO doesn't accept this API key`"))
+		return
+	}
+	if err != nil {
+		log.Print(err)
+		b.send(newMessage(m.Chat.ID, "`Failed to check the key, try again later`"))
+		return
+	}
+	if err = b.accounts.Link(m.From.ID, me.Username, apiKey, time.Now()); err != nil {
+		log.Print(err)
+		return
+	}
+
+	text := fmt.Sprintf("`Linked DEV.TO account @%s. Your reading list: /readinglist`", me.Username)
+	if tags, err := b.seedSubscription(m, apiKey); err != nil {
+		log.Print(err)
+	} else if len(tags) > 0 {
+		text += fmt.Sprintf("\n`Subscribed to your followed tags %s, change it with /subscribe`", strings.Join(tags, ", "))
+	}
+	b.send(newMessage(m.Chat.ID, text))
+}
+
+// seedSubscription subscribes private chat of the sender of message to the most followed tags of the DEV.TO account
+// if the chat has no subscription and returns the tags.
+func (b *Bot) seedSubscription(m *tgbotapi.Message, apiKey secret.Value) ([]string, error) {
+	userID := m.From.ID
+	if _, ok, err := b.subscriptions.Get(userID); err != nil || ok {
+		return nil, err
+	}
+	followed, err := b.devto.GetFollowedTags(apiKey.Reveal())
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(followed, func(i, j int) bool {
+		return followed[i].Points > followed[j].Points
+	})
+
+	sub := subscription.Subscription{ChatID: userID, Limit: defaultDigestLimit, Hour: defaultDigestHour}
+	for _, t := range followed {
+		if len(sub.Tags) == linkSeedTags {
+			break
+		}
+		if tag := strings.ToLower(t.Name); tagRgxp.MatchString(tag) {
+			sub.Tags = append(sub.Tags, tag)
+		}
+	}
+	if len(sub.Tags) == 0 {
+		return nil, nil
+	}
+	if err = b.subscriptions.Put(sub); err != nil {
+		return nil, err
+	}
+	b.record(m, "subscribe", nil, sub)
+	return sub.Tags, nil
+}
+
+// unlink forgets API key of user.
+func (b *Bot) unlink(m *tgbotapi.Message) {
+	if b.accounts == nil {
+		b.send(newMessage(m.Chat.ID, linkDisabledText))
+		return
+	}
+	_, ok, err := b.accounts.Get(m.From.ID)
+	if err != nil {
+		log.Print(err)
+		return
+	}
+	if !ok {
+		b.send(newMessage(m.Chat.ID, "`Your DEV.TO account is not linked`"))
+		return
+	}
+	if err = b.accounts.Unlink(m.From.ID); err != nil {
+		log.Print(err)
+		return
+	}
+	b.send(newMessage(m.Chat.ID, "`Unlinked, your API key is deleted.`\n"+revokeHint))
+}
+
+// readingList shows the DEV.TO reading list of user.
+func (b *Bot) readingList(m *tgbotapi.Message) {
+	if b.accounts == nil {
+		b.send(newMessage(m.Chat.ID, linkDisabledText))
+		return
+	}
+	apiKey, ok, err := b.accounts.Key(m.From.ID)
+	if err != nil {
+		log.Print(err)
+		return
+	}
+	if !ok {
+		b.send(newMessage(m.Chat.ID, noAccountText))
+		return
+	}
+
+	items, err := b.devto.GetReadingList(apiKey.Reveal(), 1, readingListShown)
+	if errors.Is(err, devto.ErrUnauthorized) {
+		b.send(newMessage(m.Chat.ID, revokedKeyText))
+		return
+	}
+	if err != nil {
+		log.Print(err)
+		return
+	}
+	if len(items) == 0 {
+		b.send(newMessage(m.Chat.ID, "`Your reading list is empty`"))
+		return
+	}
+
+	articles := make(devto.Articles, 0, len(items))
+	for _, item := range items {
+		articles = append(articles, item.Article)
+	}
+	articles = b.moderate(m.Chat.ID, articles)
+	b.withGrowth(articles)
+	b.send(newMessage(m.Chat.ID, "`Your DEV.TO reading list:`\n\n"+articles.WriteArticles(readingListShown)))
+}
+
+// logText returns text of message safe to log.
+func logText(m *tgbotapi.Message) string {
+	if m.Command() == "link" {
+		return secretCommandText
+	}
+	return m.Text
+}
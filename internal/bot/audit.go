@@ -0,0 +1,181 @@
+package bot
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alebsys/telegram-article-bot/internal/audit"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	auditUsage        = "`Usage: /audit [action] [number], /audit export`"
+	auditShown        = 10
+	auditMaxShown     = 50
+	auditAccessAction = "access"
+	// auditAccessListsAction records the whole access lists of the bot, which are compared with the lists
+	// of the next start. It is not shown in chats.
+	auditAccessListsAction = "access_lists"
+)
+
+// record writes an administrative action of the sender of message to the audit log.
+func (b *Bot) record(m *tgbotapi.Message, action string, before, after interface{}) {
+	e := audit.Entry{Time: time.Now(), ActorID: m.From.ID, Actor: m.From.UserName, ChatID: m.Chat.ID, Action: action}
+	if err := b.audit.Append(e, before, after); err != nil {
+		log.Print(err)
+	}
+}
+
+// recordAccess writes the access lists of the bot to the audit log when they differ from the last recorded ones.
+// Every changed grant is also recorded in the chat it applies to, so administrators see it with /audit.
+func (b *Bot) recordAccess() error {
+	last, err := b.audit.List(audit.Query{Action: auditAccessListsAction}, 1)
+	if err != nil {
+		return err
+	}
+	var before interface{}
+	var prev Access
+	if len(last) > 0 {
+		if err = json.Unmarshal(last[0].After, &prev); err != nil {
+			return fmt.Errorf("error when unmarshal recorded access: %v", err)
+		}
+		if reflect.DeepEqual(prev, b.access) {
+			return nil
+		}
+		before = prev
+	} else if len(b.access.Users) == 0 && len(b.access.Chats) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	for _, c := range accessChanges(prev, b.access) {
+		e := audit.Entry{Time: now, ChatID: c.chatID, Action: auditAccessAction}
+		if err = b.audit.Append(e, map[string]bool{c.list: !c.allowed}, map[string]bool{c.list: c.allowed}); err != nil {
+			return err
+		}
+	}
+	return b.audit.Append(audit.Entry{Time: now, Action: auditAccessListsAction}, before, b.access)
+}
+
+// accessChange is a chat added to or removed from an access list.
+type accessChange struct {
+	chatID  int64
+	list    string
+	allowed bool
+}
+
+// accessChanges returns chats added to or removed from access lists. A user is granted access
+// in their private chat, which has the id of the user.
+func accessChanges(before, after Access) []accessChange {
+	var changes []accessChange
+	diff := func(list string, before, after []int64) {
+		was := make(map[int64]bool)
+		for _, id := range before {
+			was[id] = true
+		}
+		for _, id := range after {
+			if !was[id] {
+				changes = append(changes, accessChange{chatID: id, list: list, allowed: true})
+			}
+			delete(was, id)
+		}
+		for _, id := range before {
+			if was[id] {
+				changes = append(changes, accessChange{chatID: id, list: list, allowed: false})
+			}
+		}
+	}
+	diff("allow_users", before.Users, after.Users)
+	diff("allow_chats", before.Chats, after.Chats)
+	return changes
+}
+
+// showAudit shows the last administrative actions in the chat or sends all of them as JSON lines.
+// Only administrators can see the audit log of a group.
+func (b *Bot) showAudit(m *tgbotapi.Message) {
+	args := strings.Fields(m.CommandArguments())
+	export, q, limit, err := parseAuditArgs(args)
+	if err != nil {
+		b.send(newMessage(m.Chat.ID, "`"+err.Error()+"`\n"+auditUsage))
+		return
+	}
+	if !b.isAdmin(m.Chat, m.From.ID) {
+		b.send(newMessage(m.Chat.ID, "`Only administrators can see the audit log`"))
+		return
+	}
+	q.ChatID = m.Chat.ID
+
+	if export {
+		buf := new(bytes.Buffer)
+		if err = b.audit.WriteJSONL(buf, q); err != nil {
+			log.Print(err)
+			return
+		}
+		doc := tgbotapi.NewDocument(m.Chat.ID, tgbotapi.FileBytes{Name: "audit.jsonl", Bytes: buf.Bytes()})
+		doc.Caption = "Audit log of this chat"
+		b.send(doc)
+		return
+	}
+
+	entries, err := b.audit.List(q, limit)
+	if err != nil {
+		log.Print(err)
+		return
+	}
+	if len(entries) == 0 {
+		b.send(newMessage(m.Chat.ID, "`No administrative actions in this chat yet`"))
+		return
+	}
+	b.send(newMessage(m.Chat.ID, "```\n"+writeAudit(entries)+"```"))
+}
+
+// parseAuditArgs parses 'export' or an optional action followed by an optional number of entries.
+func parseAuditArgs(args []string) (bool, audit.Query, int, error) {
+	var q audit.Query
+	if len(args) == 1 && args[0] == "export" {
+		return true, q, 0, nil
+	}
+	if len(args) > 2 {
+		return false, q, 0, fmt.Errorf("too many arguments")
+	}
+	limit := auditShown
+	for i, arg := range args {
+		if n, err := strconv.Atoi(arg); err == nil {
+			if n < 1 || n > auditMaxShown {
+				return false, q, 0, fmt.Errorf("number must be from 1 to %d", auditMaxShown)
+			}
+			limit = n
+			continue
+		}
+		if i > 0 {
+			return false, q, 0, fmt.Errorf("number must be a number")
+		}
+		q.Action = strings.ToLower(strings.TrimPrefix(arg, "/"))
+	}
+	return false, q, limit, nil
+}
+
+func writeAudit(entries []audit.Entry) string {
+	buf := new(bytes.Buffer)
+	for _, e := range entries {
+		actor := "@" + e.Actor
+		if e.Actor == "" {
+			actor = strconv.FormatInt(e.ActorID, 10)
+		}
+		buf.WriteString(fmt.Sprintf("%s %s %s", e.Time.UTC().Format("2006-01-02 15:04"), actor, e.Action))
+		if len(e.Before) > 0 {
+			buf.WriteString(" " + string(e.Before) + " ->")
+		}
+		if len(e.After) > 0 {
+			buf.WriteString(" " + string(e.After))
+		}
+		buf.WriteString("\n")
+	}
+	return buf.String()
+}
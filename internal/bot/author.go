@@ -0,0 +1,139 @@
+package bot
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alebsys/telegram-article-bot/internal/archive"
+	"github.com/alebsys/telegram-article-bot/internal/devto"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	authorUsage       = "`Usage: /author @username`"
+	leadersUsage      = "`Usage: /leaders go 30`"
+	authorArticles    = 30
+	authorTopArticles = 5
+	leadersShown      = 10
+	leadersMaxDays    = 365
+)
+
+var usernameRgxp = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+// author shows profile of DEV.TO user with the user's recent top articles.
+func (b *Bot) author(m *tgbotapi.Message) {
+	username := strings.TrimPrefix(strings.TrimSpace(m.CommandArguments()), "@")
+	if !usernameRgxp.MatchString(username) {
+		b.send(newMessage(m.Chat.ID, authorUsage))
+		return
+	}
+
+	profile, err := b.devto.GetUser(username)
+	if errors.Is(err, devto.ErrNotFound) {
+		b.send(newMessage(m.Chat.ID, "`There is no such user on DEV.TO`"))
+		return
+	}
+	if err != nil {
+		log.Print(err)
+		return
+	}
+	articles, err := b.devto.GetUserArticles(username, authorArticles)
+	if err != nil {
+		log.Print(err)
+		return
+	}
+	if err = b.archive.Put(*articles...); err != nil {
+		log.Print(err)
+	}
+	top := b.moderate(m.Chat.ID, append(devto.Articles(nil), *articles...))
+	sort.SliceStable(top, func(i, j int) bool {
+		return top[i].Score > top[j].Score
+	})
+	top = top.Head(authorTopArticles)
+	b.withGrowth(top)
+
+	msg := newMessage(m.Chat.ID, writeProfile(profile)+"\n"+top.WriteArticles(authorTopArticles))
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonURL("➕ Follow on DEV.TO", "https://dev.to/"+profile.Username),
+	))
+	b.send(msg)
+}
+
+// writeProfile makes a card of the author. Name, bio and location are free text of the author, so they are escaped.
+func writeProfile(p *devto.Profile) string {
+	buf := new(bytes.Buffer)
+	buf.WriteString(fmt.Sprintf("`%s (@%s)`\n", devto.EscapeCode(p.Name), p.Username))
+	if p.Summary != "" {
+		buf.WriteString(devto.EscapeMarkdown(p.Summary) + "\n")
+	}
+	var details []string
+	if p.Location != "" {
+		details = append(details, devto.EscapeCode(p.Location))
+	}
+	if p.JoinedAt != "" {
+		details = append(details, "joined "+p.JoinedAt)
+	}
+	if p.GitHub != "" {
+		details = append(details, "github.com/"+p.GitHub)
+	}
+	if len(details) > 0 {
+		buf.WriteString("`" + strings.Join(details, " · ") + "`\n")
+	}
+	return buf.String()
+}
+
+// leaders ranks authors by reactions to their articles with a tag in the archive.
+func (b *Bot) leaders(m *tgbotapi.Message) {
+	tag, days, err := parseLeadersArgs(strings.Fields(m.CommandArguments()))
+	if err != nil {
+		b.send(newMessage(m.Chat.ID, "`"+err.Error()+"`\n"+leadersUsage))
+		return
+	}
+
+	from := time.Now().AddDate(0, 0, -days)
+	articles, err := b.archive.Since(from)
+	if err != nil {
+		log.Print(err)
+		return
+	}
+	leaders := archive.Leaders(b.moderate(m.Chat.ID, articles), tag, from, leadersShown)
+	if len(leaders) == 0 {
+		b.send(newMessage(m.Chat.ID, "`No articles with this tag in the archive yet`"))
+		return
+	}
+
+	rows := make([][]string, 0, len(leaders))
+	for i, l := range leaders {
+		rows = append(rows, []string{strconv.Itoa(i + 1), "@" + l.Username, strconv.Itoa(l.Articles), strconv.Itoa(l.Reactions)})
+	}
+	text := fmt.Sprintf("`Top authors of #%s, last %d days:`\n```\n%s```\n`More about an author: /author @%s`",
+		tag, days, writeTable([]string{"#", "author", "posts", "reactions"}, rows), leaders[0].Username)
+	b.send(newMessage(m.Chat.ID, text))
+}
+
+// parseLeadersArgs parses a tag and an optional number of days.
+func parseLeadersArgs(args []string) (string, int, error) {
+	if len(args) == 0 || len(args) > 2 {
+		return "", 0, fmt.Errorf("enter a tag and period")
+	}
+	tag := strings.ToLower(strings.TrimPrefix(args[0], "#"))
+	if !tagRgxp.MatchString(tag) {
+		return "", 0, fmt.Errorf("bad tag %q", tag)
+	}
+	days := compareDefaultDays
+	if len(args) > 1 {
+		n, err := strconv.Atoi(strings.TrimSuffix(args[1], "d"))
+		if err != nil || n < 1 || n > leadersMaxDays {
+			return "", 0, fmt.Errorf("period must be from 1 to %d days", leadersMaxDays)
+		}
+		days = n
+	}
+	return tag, days, nil
+}
@@ -0,0 +1,305 @@
+package bot
+
+import (
+	"log"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"github.com/alebsys/telegram-article-bot/internal/account"
+	"github.com/alebsys/telegram-article-bot/internal/archive"
+	"github.com/alebsys/telegram-article-bot/internal/audit"
+	"github.com/alebsys/telegram-article-bot/internal/bookmarks"
+	"github.com/alebsys/telegram-article-bot/internal/debounce"
+	"github.com/alebsys/telegram-article-bot/internal/devto"
+	"github.com/alebsys/telegram-article-bot/internal/draft"
+	"github.com/alebsys/telegram-article-bot/internal/history"
+	"github.com/alebsys/telegram-article-bot/internal/link"
+	"github.com/alebsys/telegram-article-bot/internal/moderation"
+	"github.com/alebsys/telegram-article-bot/internal/poll"
+	"github.com/alebsys/telegram-article-bot/internal/profile"
+	"github.com/alebsys/telegram-article-bot/internal/reminder"
+	"github.com/alebsys/telegram-article-bot/internal/scheduler"
+	"github.com/alebsys/telegram-article-bot/internal/secret"
+	"github.com/alebsys/telegram-article-bot/internal/settings"
+	"github.com/alebsys/telegram-article-bot/internal/store"
+	"github.com/alebsys/telegram-article-bot/internal/subscription"
+	"github.com/alebsys/telegram-article-bot/internal/teamlist"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	defaultRetention    = 30 * 24 * time.Hour
+	defaultHistoryLimit = 20
+	purgeInterval       = time.Hour
+	pendingLinkTTL      = 24 * time.Hour
+	digestInterval      = 5 * time.Minute
+	reminderInterval    = 5 * time.Minute
+	crawlInterval       = time.Hour
+	sampleInterval      = time.Hour
+	// archiveRetention covers the longest period of /chart, /compare and /leaders.
+	archiveRetention = 366 * 24 * time.Hour
+)
+
+// Bot handles updates from Telegram.
+type Bot struct {
+	api           *tgbotapi.BotAPI
+	devto         *devto.Client
+	history       *history.History
+	archive       *archive.Archive
+	teamLists     *teamlist.TeamLists
+	subscriptions *subscription.Subscriptions
+	polls         *poll.Polls
+	scheduler     *scheduler.Scheduler
+	settings      *settings.Settings
+	unfurlChats   *debounce.Debouncer
+	unfurlLinks   *debounce.Debouncer
+	resolver      *link.Resolver
+	pending       *link.Pending
+	bookmarks     *bookmarks.Bookmarks
+	reminders     *reminder.Log
+	profiles      *profile.Profiles
+	accounts      *account.Accounts
+	drafts        *draft.Drafts
+	moderation    *moderation.Moderation
+	audit         *audit.Log
+	vault         *secret.Vault
+	access        Access
+	webClient     *http.Client
+	userData      []userData
+	rand          *rand.Rand
+	retention     time.Duration
+	historyLimit  int
+	// sampleCursor is the id of the last article taken by resample
+	sampleCursor int
+}
+
+// Option configures Bot.
+type Option func(*Bot)
+
+// WithRetention sets how long query history is kept. Zero keeps history forever.
+func WithRetention(d time.Duration) Option {
+	return func(b *Bot) {
+		b.retention = d
+	}
+}
+
+// WithHistoryLimit sets how many last queries of every user are kept.
+func WithHistoryLimit(n int) Option {
+	return func(b *Bot) {
+		b.historyLimit = n
+	}
+}
+
+// WithDevto sets client of DEV.TO API.
+func WithDevto(c *devto.Client) Option {
+	return func(b *Bot) {
+		b.devto = c
+	}
+}
+
+// WithVault sets Vault which keeps API keys of linked DEV.TO accounts.
+// Linking of accounts is disabled without it.
+func WithVault(v *secret.Vault) Option {
+	return func(b *Bot) {
+		b.vault = v
+	}
+}
+
+// WithWebClient sets HTTP client which fetches pages of links, e.g. one using a proxy.
+func WithWebClient(h *http.Client) Option {
+	return func(b *Bot) {
+		b.webClient = h
+	}
+}
+
+// WithArchive sets archive of articles, e.g. one shared by bots reading the same DEV.TO host.
+func WithArchive(a *archive.Archive) Option {
+	return func(b *Bot) {
+		b.archive = a
+	}
+}
+
+// WithAccess limits users and chats which can use the bot.
+func WithAccess(a Access) Option {
+	return func(b *Bot) {
+		b.access = a
+	}
+}
+
+// New makes Bot which keeps its state in s.
+func New(api *tgbotapi.BotAPI, s store.Store, opts ...Option) *Bot {
+	b := &Bot{
+		api:          api,
+		devto:        devto.DefaultClient,
+		retention:    defaultRetention,
+		historyLimit: defaultHistoryLimit,
+		rand:         rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+
+	b.history = history.New(s, b.historyLimit)
+	if b.archive == nil {
+		b.archive = archive.New(s)
+	}
+	b.teamLists = teamlist.New(s)
+	b.subscriptions = subscription.New(s)
+	b.polls = poll.New(s)
+	b.scheduler = scheduler.New(s)
+	b.settings = settings.New(s, knownSettings...)
+	b.unfurlChats = debounce.New(unfurlChatWindow)
+	b.unfurlLinks = debounce.New(unfurlLinkWindow)
+	b.resolver = link.NewResolver(b.devto, b.webClient, 0)
+	b.pending = link.NewPending(s)
+	b.bookmarks = bookmarks.New(s)
+	b.reminders = reminder.NewLog(s)
+	b.profiles = profile.New(s)
+	b.drafts = draft.New(s)
+	b.moderation = moderation.New(s)
+	b.audit = audit.New(s)
+
+	b.register("history", b.history)
+	b.register("teamlist", b.teamLists)
+	b.register("subscription", b.subscriptions)
+	b.register("polls", b.polls)
+	b.register("settings", b.settings)
+	b.register("bookmarks", b.bookmarks)
+	b.register("links", b.pending)
+	b.register("reminders", b.reminders)
+	b.register("profile", b.profiles)
+	b.register("drafts", b.drafts)
+	b.register("moderation", b.moderation)
+	b.register("audit", b.audit)
+	if b.vault != nil {
+		b.accounts = account.New(s, b.vault)
+		b.register("account", b.accounts)
+	}
+
+	b.scheduler.Every(purgeInterval, b.purge)
+	b.scheduler.Every(digestInterval, b.sendDigests)
+	b.scheduler.Every(reminderInterval, b.sendReminders)
+	b.scheduler.Handle(pollStartJob, b.startScheduledPoll)
+	b.scheduler.Handle(pollCloseJob, b.closePoll)
+	b.scheduler.Every(crawlInterval, b.crawl)
+	b.scheduler.Every(sampleInterval, b.resample)
+	b.scheduler.Handle(trendingJob, b.trendingReport)
+	return b
+}
+
+// Run receives updates from Telegram and handles them until updates channel is closed.
+// The scheduler of the bot stops when Run returns.
+func (b *Bot) Run() {
+	if err := b.scheduleTrendingReport(); err != nil {
+		log.Print(err)
+	}
+	if err := b.recordAccess(); err != nil {
+		log.Print(err)
+	}
+	stop := make(chan struct{})
+	defer close(stop)
+	go b.scheduler.Run(0, stop)
+
+	u := tgbotapi.NewUpdate(0)
+	u.Timeout = 60
+	updates := b.api.GetUpdatesChan(u)
+
+	for update := range updates {
+		b.handleUpdate(update)
+	}
+}
+
+// handleUpdate handles update, a panic only fails this update.
+func (b *Bot) handleUpdate(update tgbotapi.Update) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("panic when handles update %d: %v", update.UpdateID, r)
+		}
+	}()
+
+	switch {
+	case update.CallbackQuery != nil:
+		q := update.CallbackQuery
+		if q.Message != nil && !b.access.Allows(q.Message.Chat.ID, q.From.ID) {
+			b.answer(q, "")
+			return
+		}
+		b.handleCallback(q)
+	case update.PollAnswer != nil:
+		b.handlePollAnswer(update.PollAnswer)
+	case update.Message != nil:
+		if update.Message.From == nil || !b.allowed(update.Message) {
+			return
+		}
+		b.handleMessage(update.Message)
+	}
+}
+
+// purge removes history older than retention period, links nobody has chosen what to do with,
+// abandoned drafts and old samples of reactions.
+func (b *Bot) purge(now time.Time) {
+	if b.retention > 0 {
+		if err := b.history.Purge(now.Add(-b.retention)); err != nil {
+			log.Print(err)
+		}
+	}
+	if err := b.pending.Purge(now.Add(-pendingLinkTTL)); err != nil {
+		log.Print(err)
+	}
+	if err := b.drafts.Purge(now.Add(-draftTTL)); err != nil {
+		log.Print(err)
+	}
+	if err := b.archive.PurgeSamples(now.Add(-sampleRetention)); err != nil {
+		log.Print(err)
+	}
+	if err := b.archive.Purge(now.Add(-archiveRetention)); err != nil {
+		log.Print(err)
+	}
+}
+
+// newMessage makes markdown message without web page preview.
+func newMessage(chatID int64, text string) tgbotapi.MessageConfig {
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ParseMode = "markdown"
+	msg.DisableWebPagePreview = true
+	return msg
+}
+
+// send sends c and logs error if any.
+func (b *Bot) send(c tgbotapi.Chattable) {
+	if _, err := b.api.Send(c); err != nil {
+		log.Print(err)
+	}
+}
+
+// touch records interaction of user and returns how long the user was away, see profile.Profiles.Touch.
+func (b *Bot) touch(userID int64) time.Duration {
+	away, err := b.profiles.Touch(userID, time.Now())
+	if err != nil {
+		log.Print(err)
+	}
+	return away
+}
+
+// isAdmin reports whether user is an administrator of chat. Everyone is an administrator of a private chat.
+func (b *Bot) isAdmin(chat *tgbotapi.Chat, userID int64) bool {
+	if chat.IsPrivate() {
+		return true
+	}
+	member, err := b.api.GetChatMember(tgbotapi.GetChatMemberConfig{
+		ChatConfigWithUser: tgbotapi.ChatConfigWithUser{ChatID: chat.ID, UserID: userID},
+	})
+	if err != nil {
+		log.Print(err)
+		return false
+	}
+	return member.IsCreator() || member.IsAdministrator()
+}
+
+// answer acknowledges callback query with optional text.
+func (b *Bot) answer(q *tgbotapi.CallbackQuery, text string) {
+	if _, err := b.api.Request(tgbotapi.NewCallback(q.ID, text)); err != nil {
+		log.Print(err)
+	}
+}
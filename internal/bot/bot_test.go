@@ -0,0 +1,489 @@
+package bot
+
+import (
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alebsys/telegram-article-bot/internal/devto"
+	"github.com/alebsys/telegram-article-bot/internal/link"
+	"github.com/alebsys/telegram-article-bot/internal/store"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// newTestBot returns Bot with memory store talking to a fake Telegram API which counts sent messages.
+func newTestBot(t *testing.T) (*Bot, *int32) {
+	var sent int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/getMe"):
+			w.Write([]byte(`{"ok": true, "result": {"id": 1, "is_bot": true, "username": "test_bot"}}`))
+		case strings.HasSuffix(r.URL.Path, "/sendMessage"):
+			n := atomic.AddInt32(&sent, 1)
+			w.Write([]byte(`{"ok": true, "result": {"message_id": ` + strconv.Itoa(int(n)) + `, "chat": {"id": 1}}}`))
+		default:
+			w.Write([]byte(`{"ok": true, "result": true}`))
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	api, err := tgbotapi.NewBotAPIWithAPIEndpoint("123:ABC", srv.URL+"/bot%s/%s")
+	if err != nil {
+		t.Fatalf("NewBotAPI: got error %v", err)
+	}
+	return New(api, store.NewMemory()), &sent
+}
+
+func TestDevtoPaths(t *testing.T) {
+	cases := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"no links", "hello", nil},
+		{"article link", "look https://dev.to/ben/hello-go-1a2b!", []string{"ben/hello-go-1a2b"}},
+		{"duplicates and tags", "https://dev.to/t/go https://dev.to/ben/a https://www.dev.to/ben/a", []string{"ben/a"}},
+		{"too many links", "https://dev.to/a/1 https://dev.to/a/2 https://dev.to/a/3 https://dev.to/a/4", []string{"a/1", "a/2", "a/3"}},
+		{"other site", "https://example.com/ben/a", nil},
+	}
+	for _, c := range cases {
+		if got := devtoPaths(c.text); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("devtoPaths: %s; got %v; want %v", c.name, got, c.want)
+		}
+	}
+}
+
+func TestParseSubscription(t *testing.T) {
+	cases := []struct {
+		name    string
+		args    []string
+		tags    []string
+		limit   int
+		minutes int
+		hour    int
+		failed  bool
+	}{
+		{"tags only", []string{"Go,rust"}, []string{"go", "rust"}, defaultDigestLimit, 0, defaultDigestHour, false},
+		{"tags, limit and hour", []string{"go", "3", "18"}, []string{"go"}, 3, 0, 18, false},
+		{"reading time", []string{"go", "30m"}, []string{"go"}, 0, 30, defaultDigestHour, false},
+		{"bad tag", []string{"c++"}, nil, 0, 0, 0, true},
+		{"bad limit", []string{"go", "100"}, nil, 0, 0, 0, true},
+		{"bad reading time", []string{"go", "0m"}, nil, 0, 0, 0, true},
+		{"bad hour", []string{"go", "5", "24"}, nil, 0, 0, 0, true},
+		{"extra args", []string{"go", "5", "9", "1"}, nil, 0, 0, 0, true},
+	}
+	for _, c := range cases {
+		sub, err := parseSubscription(1, c.args)
+		if (err != nil) != c.failed {
+			t.Errorf("parseSubscription: %s; got error %v; want error %v", c.name, err, c.failed)
+			continue
+		}
+		if c.failed {
+			continue
+		}
+		if !reflect.DeepEqual(sub.Tags, c.tags) || sub.Limit != c.limit || sub.Minutes != c.minutes || sub.Hour != c.hour {
+			t.Errorf("parseSubscription: %s; got %+v", c.name, sub)
+		}
+	}
+}
+
+func TestParsePollArgs(t *testing.T) {
+	cases := []struct {
+		name   string
+		args   []string
+		n      int
+		d      time.Duration
+		failed bool
+	}{
+		{"defaults", nil, defaultPollCandidates, defaultPollDuration, false},
+		{"candidates", []string{"3"}, 3, defaultPollDuration, false},
+		{"candidates and hours", []string{"4", "24"}, 4, 24 * time.Hour, false},
+		{"one candidate", []string{"1"}, 0, 0, true},
+		{"too long", []string{"4", "200"}, 0, 0, true},
+	}
+	for _, c := range cases {
+		n, d, err := parsePollArgs(c.args)
+		if (err != nil) != c.failed {
+			t.Errorf("parsePollArgs: %s; got error %v; want error %v", c.name, err, c.failed)
+			continue
+		}
+		if n != c.n || d != c.d {
+			t.Errorf("parsePollArgs: %s; got %d, %v; want %d, %v", c.name, n, d, c.n, c.d)
+		}
+	}
+}
+
+func TestParseSavedQuery(t *testing.T) {
+	cases := []struct {
+		name string
+		args string
+		tags []string
+		text string
+	}{
+		{"empty", "", nil, ""},
+		{"tags", "#Review #go", []string{"#review", "#go"}, ""},
+		{"quoted text", `"error handling"`, nil, "error handling"},
+		{"tag and text", `#go "context"`, []string{"#go"}, "context"},
+		{"unquoted text", "context package", nil, "context package"},
+	}
+	for _, c := range cases {
+		tags, text := parseSavedQuery(c.args)
+		if !reflect.DeepEqual(tags, c.tags) || text != c.text {
+			t.Errorf("parseSavedQuery: %s; got %v, %q; want %v, %q", c.name, tags, text, c.tags, c.text)
+		}
+	}
+}
+
+func TestParseChartArgs(t *testing.T) {
+	cases := []struct {
+		name   string
+		args   []string
+		tags   []string
+		days   int
+		failed bool
+	}{
+		{"tags", []string{"go,Rust"}, []string{"go", "rust"}, chartDefaultDays, false},
+		{"period", []string{"go", "90d"}, []string{"go"}, 90, false},
+		{"period without suffix", []string{"go", "7"}, []string{"go"}, 7, false},
+		{"no tags", nil, nil, 0, true},
+		{"bad tag", []string{"c++"}, nil, 0, true},
+		{"too many tags", []string{"a,b,c,d,e,f"}, nil, 0, true},
+		{"too long", []string{"go", "400d"}, nil, 0, true},
+	}
+	for _, c := range cases {
+		tags, days, err := parseChartArgs(c.args)
+		if (err != nil) != c.failed {
+			t.Errorf("parseChartArgs: %s; got error %v; want error %v", c.name, err, c.failed)
+			continue
+		}
+		if !reflect.DeepEqual(tags, c.tags) || days != c.days {
+			t.Errorf("parseChartArgs: %s; got %v, %d; want %v, %d", c.name, tags, days, c.tags, c.days)
+		}
+	}
+}
+
+func TestParseCompareArgs(t *testing.T) {
+	cases := []struct {
+		name   string
+		args   []string
+		tags   []string
+		days   int
+		failed bool
+	}{
+		{"tags", []string{"go", "#Rust"}, []string{"go", "rust"}, compareDefaultDays, false},
+		{"period", []string{"go", "rust", "zig", "7"}, []string{"go", "rust", "zig"}, 7, false},
+		{"only period", []string{"30"}, nil, 0, true},
+		{"bad tag", []string{"c++", "go"}, nil, 0, true},
+		{"too long", []string{"go", "1000"}, nil, 0, true},
+	}
+	for _, c := range cases {
+		tags, days, err := parseCompareArgs(c.args)
+		if (err != nil) != c.failed {
+			t.Errorf("parseCompareArgs: %s; got error %v; want error %v", c.name, err, c.failed)
+			continue
+		}
+		if !reflect.DeepEqual(tags, c.tags) || days != c.days {
+			t.Errorf("parseCompareArgs: %s; got %v, %d; want %v, %d", c.name, tags, days, c.tags, c.days)
+		}
+	}
+}
+
+func TestWriteTable(t *testing.T) {
+	got := writeTable([]string{"tag", "posts", "author"}, [][]string{{"go", "12", "@ann"}, {"rust", "7", "-"}})
+	want := "tag   posts  author\ngo       12  @ann\nrust      7  -\n"
+	if got != want {
+		t.Errorf("writeTable: got %q; want %q", got, want)
+	}
+}
+
+func TestParseRandomArgs(t *testing.T) {
+	cases := []struct {
+		name     string
+		args     []string
+		tag      string
+		minScore int
+		failed   bool
+	}{
+		{"defaults", nil, "", randomMinScore, false},
+		{"tag", []string{"#Go"}, "go", randomMinScore, false},
+		{"tag and score", []string{"go", "50"}, "go", 50, false},
+		{"score", []string{"0"}, "", 0, false},
+		{"two tags", []string{"go", "rust"}, "", 0, true},
+		{"negative score", []string{"go", "-1"}, "", 0, true},
+	}
+	for _, c := range cases {
+		tag, minScore, err := parseRandomArgs(c.args)
+		if (err != nil) != c.failed {
+			t.Errorf("parseRandomArgs: %s; got error %v; want error %v", c.name, err, c.failed)
+			continue
+		}
+		if tag != c.tag || minScore != c.minScore {
+			t.Errorf("parseRandomArgs: %s; got %q, %d; want %q, %d", c.name, tag, minScore, c.tag, c.minScore)
+		}
+	}
+}
+
+func TestWeightedPick(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	if _, ok := weightedPick(nil, r); ok {
+		t.Errorf("weightedPick: no articles; got ok")
+	}
+
+	articles := devto.Articles{{ID: 1, Score: 0}, {ID: 2, Score: 99}}
+	var popular int
+	for i := 0; i < 1000; i++ {
+		if a, _ := weightedPick(articles, r); a.ID == 2 {
+			popular++
+		}
+	}
+	if popular < 950 || popular == 1000 {
+		t.Errorf("weightedPick: got the popular article %d times of 1000; want about 990", popular)
+	}
+}
+
+func TestParseLeadersArgs(t *testing.T) {
+	cases := []struct {
+		name   string
+		args   []string
+		tag    string
+		days   int
+		failed bool
+	}{
+		{"tag", []string{"#Go"}, "go", compareDefaultDays, false},
+		{"tag and period", []string{"go", "90"}, "go", 90, false},
+		{"no tag", nil, "", 0, true},
+		{"bad period", []string{"go", "week"}, "", 0, true},
+	}
+	for _, c := range cases {
+		tag, days, err := parseLeadersArgs(c.args)
+		if (err != nil) != c.failed {
+			t.Errorf("parseLeadersArgs: %s; got error %v; want error %v", c.name, err, c.failed)
+			continue
+		}
+		if tag != c.tag || days != c.days {
+			t.Errorf("parseLeadersArgs: %s; got %q, %d; want %q, %d", c.name, tag, days, c.tag, c.days)
+		}
+	}
+}
+
+func TestLogText(t *testing.T) {
+	cases := []struct {
+		text string
+		want string
+	}{
+		{"/article go 10 5", "/article go 10 5"},
+		{"/link s3cr3t", secretCommandText},
+	}
+	for _, c := range cases {
+		m := &tgbotapi.Message{Text: c.text, Entities: []tgbotapi.MessageEntity{{Type: "bot_command", Offset: 0, Length: strings.Index(c.text, " ")}}}
+		if got := logText(m); got != c.want {
+			t.Errorf("logText: %q; got %q; want %q", c.text, got, c.want)
+		}
+	}
+}
+
+func TestParseDraftTags(t *testing.T) {
+	cases := []struct {
+		name   string
+		text   string
+		tags   []string
+		failed bool
+	}{
+		{"skip", " - ", nil, false},
+		{"commas and spaces", "Go, #webdev tutorial", []string{"go", "webdev", "tutorial"}, false},
+		{"too many", "a b c d e", nil, true},
+		{"bad tag", "c++", nil, true},
+	}
+	for _, c := range cases {
+		tags, err := parseDraftTags(c.text)
+		if (err != nil) != c.failed {
+			t.Errorf("parseDraftTags: %s; got error %v; want error %v", c.name, err, c.failed)
+			continue
+		}
+		if !reflect.DeepEqual(tags, c.tags) {
+			t.Errorf("parseDraftTags: %s; got %v; want %v", c.name, tags, c.tags)
+		}
+	}
+}
+
+func TestToMarkdown(t *testing.T) {
+	cases := []struct {
+		name     string
+		text     string
+		entities []tgbotapi.MessageEntity
+		want     string
+	}{
+		{"plain", "hello", nil, "hello"},
+		{"bold and link", "Go is fun", []tgbotapi.MessageEntity{
+			{Type: "bold", Offset: 0, Length: 2},
+			{Type: "text_link", Offset: 6, Length: 3, URL: "https://go.dev"},
+		}, "**Go** is [fun](https://go.dev)"},
+		{"nested", "very bold", []tgbotapi.MessageEntity{
+			{Type: "bold", Offset: 0, Length: 9},
+			{Type: "italic", Offset: 5, Length: 4},
+		}, "**very _bold_**"},
+		{"after emoji", "🎲 roll", []tgbotapi.MessageEntity{{Type: "code", Offset: 3, Length: 4}}, "🎲 `roll`"},
+		{"pre", "x := 1", []tgbotapi.MessageEntity{{Type: "pre", Offset: 0, Length: 6, Language: "go"}}, "```go\nx := 1\n```"},
+	}
+	for _, c := range cases {
+		if got := toMarkdown(c.text, c.entities); got != c.want {
+			t.Errorf("toMarkdown: %s; got %q; want %q", c.name, got, c.want)
+		}
+	}
+}
+
+func TestParseFilterArgs(t *testing.T) {
+	cases := []struct {
+		name   string
+		args   []string
+		op     string
+		kind   string
+		values []string
+		err    bool
+	}{
+		{"add tags", []string{"add", "tag", "crypto", "nft"}, "add", "tag", []string{"crypto", "nft"}, false},
+		{"remove author", []string{"remove", "author", "@spammer"}, "remove", "author", []string{"@spammer"}, false},
+		{"sfw", []string{"sfw", "on"}, "sfw", "", []string{"on"}, false},
+		{"clear", []string{"clear"}, "clear", "", nil, false},
+		{"no values", []string{"add", "word"}, "", "", nil, true},
+		{"unknown kind", []string{"add", "color", "red"}, "", "", nil, true},
+		{"bad sfw", []string{"sfw", "maybe"}, "", "", nil, true},
+		{"unknown action", []string{"drop"}, "", "", nil, true},
+	}
+	for _, c := range cases {
+		op, kind, values, err := parseFilterArgs(c.args)
+		if (err != nil) != c.err || op != c.op || kind != c.kind || !reflect.DeepEqual(values, c.values) {
+			t.Errorf("parseFilterArgs: %s; got %q %q %v %v; want %q %q %v", c.name, op, kind, values, err, c.op, c.kind, c.values)
+		}
+	}
+}
+
+func TestParseAuditArgs(t *testing.T) {
+	cases := []struct {
+		name   string
+		args   []string
+		export bool
+		action string
+		limit  int
+		err    bool
+	}{
+		{"default", nil, false, "", auditShown, false},
+		{"export", []string{"export"}, true, "", 0, false},
+		{"action", []string{"/set"}, false, "set", auditShown, false},
+		{"action and number", []string{"subscribe", "20"}, false, "subscribe", 20, false},
+		{"number", []string{"5"}, false, "", 5, false},
+		{"too many", []string{"set", "5", "x"}, false, "", 0, true},
+		{"big number", []string{"500"}, false, "", 0, true},
+		{"number after number", []string{"5", "set"}, false, "", 0, true},
+	}
+	for _, c := range cases {
+		export, q, limit, err := parseAuditArgs(c.args)
+		if (err != nil) != c.err || export != c.export || q.Action != c.action || limit != c.limit {
+			t.Errorf("parseAuditArgs: %s; got %v %q %d %v; want %v %q %d", c.name, export, q.Action, limit, err, c.export, c.action, c.limit)
+		}
+	}
+}
+
+func TestAccessChanges(t *testing.T) {
+	before := Access{Users: []int64{1, 2}, Chats: []int64{-100}}
+	after := Access{Users: []int64{2, 3}, Chats: []int64{-100, -200}}
+
+	got := accessChanges(before, after)
+	want := []accessChange{
+		{chatID: 3, list: "allow_users", allowed: true},
+		{chatID: 1, list: "allow_users", allowed: false},
+		{chatID: -200, list: "allow_chats", allowed: true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("accessChanges: got %+v; want %+v", got, want)
+	}
+	if got := accessChanges(after, after); len(got) != 0 {
+		t.Errorf("accessChanges: same access; got %+v; want none", got)
+	}
+}
+
+func TestDocumentName(t *testing.T) {
+	cases := []struct {
+		name    string
+		article devto.Article
+		want    string
+	}{
+		{"slug", devto.Article{ID: 1, Path: "/ann/hello-go-1a2b"}, "hello-go-1a2b.html"},
+		{"no path", devto.Article{ID: 2}, "article-2.html"},
+	}
+	for _, c := range cases {
+		if got := documentName(c.article); got != c.want {
+			t.Errorf("documentName: %s; got %q; want %q", c.name, got, c.want)
+		}
+	}
+}
+
+func TestRemindSnooze(t *testing.T) {
+	b, sent := newTestBot(t)
+	const userID = 1
+	day := func(d, h, m int) time.Time { return time.Date(2026, 10, d, h, m, 0, 0, time.UTC) }
+	b.settings.Set(userID, remindersSetting, "on")
+	bm, _, err := b.bookmarks.Add(userID, link.Link{URL: "https://example.com/a", Title: "A"}, day(15, 8, 0))
+	if err != nil {
+		t.Fatalf("Add: got error %v", err)
+	}
+
+	steps := []struct {
+		name   string
+		now    time.Time
+		snooze bool
+		want   int32
+	}{
+		{"first reminder", day(16, 9, 0), false, 1},
+		{"snoozed", day(16, 9, 5), true, 1},
+		{"same day", day(16, 18, 0), false, 1},
+		{"next day", day(17, 9, 0), false, 2},
+		{"after the day", day(17, 18, 0), false, 2},
+	}
+	for _, s := range steps {
+		if s.snooze {
+			err = b.snooze(userID, bm.ID, s.now)
+		} else {
+			err = b.remind(userID, s.now)
+		}
+		if err != nil {
+			t.Fatalf("remind: %s; got error %v", s.name, err)
+		}
+		if got := atomic.LoadInt32(sent); got != s.want {
+			t.Errorf("remind: %s; got %d reminders sent; want %d", s.name, got, s.want)
+		}
+	}
+}
+
+func TestWriteLink(t *testing.T) {
+	cases := []struct {
+		name string
+		link link.Link
+		want string
+	}{
+		{"plain", link.Link{URL: "https://example.com/a", Title: "A", Site: "Example", Tags: []string{"go"}},
+			"[A](https://example.com/a)\n`  Example`\n`  #go`\n"},
+		{"backticks from the page", link.Link{URL: "https://example.com/a", Title: "A", Site: "`Site`", Author: "Ann `x`", Tags: []string{"go`"}},
+			"[A](https://example.com/a)\n`  'Site' · Ann 'x'`\n`  #go'`\n"},
+	}
+	for _, c := range cases {
+		if got := writeLink(c.link, false); got != c.want {
+			t.Errorf("writeLink: %s; got %q; want %q", c.name, got, c.want)
+		}
+	}
+}
+
+func TestWriteProfile(t *testing.T) {
+	p := &devto.Profile{Username: "ann", Name: "Ann `the dev`", Summary: "snake_case fan", Location: "Berlin"}
+	want := "`Ann 'the dev' (@ann)`\nsnake\\_case fan\n`Berlin`\n"
+	if got := writeProfile(p); got != want {
+		t.Errorf("writeProfile: got %q; want %q", got, want)
+	}
+}
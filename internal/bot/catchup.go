@@ -0,0 +1,128 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/alebsys/telegram-article-bot/internal/devto"
+	"github.com/alebsys/telegram-article-bot/internal/history"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	welcomeBackAfter = 3 * 24 * time.Hour
+	defaultCatchup   = 7 * 24 * time.Hour
+	maxCatchupDays   = 30
+	catchupLimit     = 10
+	catchupTags      = 5
+
+	queryFeedback = 0.5
+	saveFeedback  = 2
+	readFeedback  = 1
+	dropFeedback  = -1
+)
+
+// catchup sends the best articles of the followed tags published since the user's last visit,
+// ranked by the user's feedback and without articles the user has already seen or saved.
+func (b *Bot) catchup(m *tgbotapi.Message) {
+	tags, err := b.followedTags(m.From.ID)
+	if err != nil {
+		log.Print(err)
+		return
+	}
+	if len(tags) == 0 {
+		b.send(newMessage(m.Chat.ID, "`You don't follow any tags yet. Use /subscribe in our private chat or search with /article`"))
+		return
+	}
+
+	prof, err := b.profiles.Get(m.From.ID)
+	if err != nil {
+		log.Print(err)
+		return
+	}
+	now := time.Now()
+	since := prof.PrevSeen
+	if since.IsZero() {
+		since = now.Add(-defaultCatchup)
+	}
+	days := int(math.Ceil(now.Sub(since).Hours() / 24))
+	if days > maxCatchupDays {
+		days = maxCatchupDays
+	}
+
+	articles, err := b.topArticles(tags, days)
+	if err != nil {
+		log.Print(err)
+		return
+	}
+	saved, err := b.savedURLs(m.From.ID)
+	if err != nil {
+		log.Print(err)
+		return
+	}
+	var missed devto.Articles
+	for _, a := range b.moderate(m.Chat.ID, articles) {
+		if !prof.HasSeen(a.ID) && !saved[a.Url] && a.PublishedAt.After(since) {
+			missed = append(missed, a)
+		}
+	}
+	prof.Rank(missed)
+	missed = missed.Head(catchupLimit)
+
+	header := fmt.Sprintf("`What you missed in %s since %s:`\n\n", strings.Join(tags, ", "), since.UTC().Format("Jan 2"))
+	if len(missed) == 0 {
+		b.send(newMessage(m.Chat.ID, header+"`Nothing new, you are all caught up`"))
+		return
+	}
+	b.withGrowth(missed)
+	msg := newMessage(m.Chat.ID, header+missed.WriteArticles(catchupLimit))
+	msg.ReplyMarkup = readKeyboard(missed)
+	b.send(msg)
+	if err = b.profiles.MarkSeen(m.From.ID, missed, now); err != nil {
+		log.Print(err)
+	}
+}
+
+// followedTags returns tags of user private subscription or, if there is none, the most queried ones.
+func (b *Bot) followedTags(userID int64) ([]string, error) {
+	sub, ok, err := b.subscriptions.Get(userID)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		return sub.Tags, nil
+	}
+
+	entries, err := b.history.List(userID)
+	if err != nil {
+		return nil, err
+	}
+	var tags []string
+	for _, t := range history.TopTags(entries, catchupTags) {
+		tags = append(tags, t.Tag)
+	}
+	return tags, nil
+}
+
+// savedURLs returns set of URLs saved by user.
+func (b *Bot) savedURLs(userID int64) (map[string]bool, error) {
+	list, err := b.bookmarks.List(userID)
+	if err != nil {
+		return nil, err
+	}
+	urls := make(map[string]bool, len(list))
+	for _, bm := range list {
+		urls[bm.URL] = true
+	}
+	return urls, nil
+}
+
+// feedback adds weight to user feedback on tags.
+func (b *Bot) feedback(userID int64, tags []string, weight float64) {
+	if err := b.profiles.AddFeedback(userID, tags, weight); err != nil {
+		log.Print(err)
+	}
+}
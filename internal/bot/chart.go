@@ -0,0 +1,106 @@
+package bot
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alebsys/telegram-article-bot/internal/archive"
+	"github.com/alebsys/telegram-article-bot/internal/chart"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	chartUsage       = "`Usage: /chart go,rust 90d`"
+	chartMaxTags     = 5
+	chartDefaultDays = 30
+	chartMaxDays     = 365
+)
+
+// showChart sends a chart of daily articles and average reactions of tags from the archive.
+func (b *Bot) showChart(m *tgbotapi.Message) {
+	tags, days, err := parseChartArgs(strings.Fields(m.CommandArguments()))
+	if err != nil {
+		b.send(newMessage(m.Chat.ID, "`"+err.Error()+"`\n"+chartUsage))
+		return
+	}
+
+	now := time.Now()
+	articles, err := b.archive.Since(now.AddDate(0, 0, -days))
+	if err != nil {
+		log.Print(err)
+		return
+	}
+	articles = b.moderate(m.Chat.ID, articles)
+
+	c := chart.Chart{
+		Title: fmt.Sprintf("%s: last %d days", strings.Join(tags, ", "), days),
+		Panels: []chart.Panel{
+			{Title: "Articles per day"},
+			{Title: "Average reactions"},
+		},
+	}
+	var total int
+	for i, tag := range tags {
+		daily := archive.Daily(articles, tag, now, days)
+		counts := make([]float64, len(daily))
+		reactions := make([]float64, len(daily))
+		for j, d := range daily {
+			counts[j] = float64(d.Count)
+			reactions[j] = d.AvgReactions()
+			total += d.Count
+			if i == 0 {
+				c.Labels = append(c.Labels, d.Date.Format("01-02"))
+			}
+		}
+		c.Panels[0].Series = append(c.Panels[0].Series, chart.Series{Name: tag, Values: counts})
+		c.Panels[1].Series = append(c.Panels[1].Series, chart.Series{Name: tag, Values: reactions})
+	}
+	if total == 0 {
+		b.send(newMessage(m.Chat.ID, "`No articles with these tags in the archive yet`"))
+		return
+	}
+
+	buf := new(bytes.Buffer)
+	if err = c.Render(buf); err != nil {
+		log.Print(err)
+		return
+	}
+	photo := tgbotapi.NewPhoto(m.Chat.ID, tgbotapi.FileBytes{Name: "chart.png", Bytes: buf.Bytes()})
+	photo.Caption = fmt.Sprintf("#%s - %d articles in %d days", strings.Join(tags, " #"), total, days)
+	b.send(photo)
+}
+
+// parseChartArgs parses comma separated tags and an optional period like "90d".
+func parseChartArgs(args []string) ([]string, int, error) {
+	if len(args) == 0 || len(args) > 2 {
+		return nil, 0, fmt.Errorf("enter tags and period")
+	}
+
+	var tags []string
+	for _, tag := range strings.Split(strings.ToLower(args[0]), ",") {
+		if tag == "" {
+			continue
+		}
+		if !tagRgxp.MatchString(tag) {
+			return nil, 0, fmt.Errorf("bad tag %q", tag)
+		}
+		tags = append(tags, tag)
+	}
+	if len(tags) == 0 || len(tags) > chartMaxTags {
+		return nil, 0, fmt.Errorf("enter from 1 to %d tags", chartMaxTags)
+	}
+
+	days := chartDefaultDays
+	if len(args) > 1 {
+		n, err := strconv.Atoi(strings.TrimSuffix(args[1], "d"))
+		if err != nil || n < 1 || n > chartMaxDays {
+			return nil, 0, fmt.Errorf("period must be from 1 to %d days", chartMaxDays)
+		}
+		days = n
+	}
+	return tags, days, nil
+}
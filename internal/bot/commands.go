@@ -0,0 +1,234 @@
+package bot
+
+import (
+	"log"
+	"strings"
+	"time"
+
+	"github.com/alebsys/telegram-article-bot/internal/devto"
+	"github.com/alebsys/telegram-article-bot/internal/history"
+	"github.com/alebsys/telegram-article-bot/internal/link"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	descp = "`Request example::\n/article go 10 5\nгде:\n* go - topic (tag);\n* 10 - search period in days;\n* 5 - number of posts.`"
+	usage = "`Commands:\n/article - find articles;\n/again - repeat the last query;\n/history - recent queries;\n/catchup - what you have missed;\n/trending - tags gaining popularity;\n/rising - articles gaining reactions today;\n/chart - chart of tags;\n/compare - compare tags;\n/random - a random good article;\n/author - profile of an author;\n/leaders - top authors of a tag;\n/stats - your statistics;\n/teamlist - reading list of the group;\n/subscribe - daily digest of tags;\n/unsubscribe - stop the digest;\n/poll - article of the week poll;\n/saved - saved articles;\n/link - link your DEV.TO account;\n/readinglist - your DEV.TO reading list;\n/unlink - unlink DEV.TO account;\n/draft - write a DEV.TO article;\n/filter - hide words, tags and authors;\n/why - why an article is hidden;\n/settings - chat settings;\n/audit - administrative actions;\n/mydata - export your data;\n/forgetme - delete your data.\n\n`"
+)
+
+func (b *Bot) handleMessage(m *tgbotapi.Message) {
+	if m.From == nil {
+		return
+	}
+	if !m.IsCommand() {
+		b.handleText(m)
+		return
+	}
+
+	log.Printf("[%s] %s", m.From.UserName, logText(m))
+
+	away := b.touch(m.From.ID)
+	if m.Chat.IsPrivate() && away >= welcomeBackAfter && m.Command() != "catchup" {
+		defer b.send(newMessage(m.Chat.ID, "`Welcome back! Enter /catchup to see what you have missed`"))
+	}
+
+	msg := newMessage(m.Chat.ID, "")
+
+	switch m.Command() {
+	case "help":
+		msg.Text = "`Hello! I can find articles of interest to you on DEV.TO\n\n`" + usage + descp
+	case "article":
+		b.article(m.Chat, m.From.ID, m.Text)
+		return
+	case "again":
+		b.again(m)
+		return
+	case "history":
+		b.showHistory(m)
+		return
+	case "stats":
+		b.stats(m)
+		return
+	case "teamlist":
+		b.teamList(m)
+		return
+	case "subscribe":
+		b.subscribe(m)
+		return
+	case "unsubscribe":
+		b.unsubscribe(m)
+		return
+	case "poll":
+		b.poll(m)
+		return
+	case "trending":
+		b.trending(m)
+		return
+	case "rising":
+		b.rising(m)
+		return
+	case "chart":
+		b.showChart(m)
+		return
+	case "compare":
+		b.compare(m)
+		return
+	case "random":
+		b.random(m)
+		return
+	case "author":
+		b.author(m)
+		return
+	case "leaders":
+		b.leaders(m)
+		return
+	case "catchup":
+		b.catchup(m)
+		return
+	case "saved":
+		b.saved(m)
+		return
+	case "link":
+		b.link(m)
+		return
+	case "unlink":
+		b.unlink(m)
+		return
+	case "readinglist":
+		b.readingList(m)
+		return
+	case "draft":
+		b.startDraft(m)
+		return
+	case "done":
+		b.finishDraft(m)
+		return
+	case "cancel":
+		b.cancelDraft(m)
+		return
+	case "filter":
+		b.showFilter(m)
+		return
+	case "why":
+		b.why(m)
+		return
+	case "audit":
+		b.showAudit(m)
+		return
+	case "settings":
+		b.showSettings(m)
+		return
+	case "set":
+		b.set(m)
+		return
+	case "mydata":
+		b.myData(m)
+		return
+	case "forgetme":
+		b.forgetMe(m)
+		return
+	default:
+		msg.Text = "`I don't know this command. Enter /help`"
+	}
+
+	b.send(msg)
+}
+
+// handleText handles messages which are not commands.
+func (b *Bot) handleText(m *tgbotapi.Message) {
+	if m.Chat.IsGroup() || m.Chat.IsSuperGroup() {
+		b.unfurl(m)
+		return
+	}
+	if !m.Chat.IsPrivate() {
+		return
+	}
+	b.touch(m.From.ID)
+	if b.draftText(m) {
+		return
+	}
+	if m.ReplyToMessage != nil && b.note(m) {
+		return
+	}
+	if urls := link.FindURLs(messageText(m)); len(urls) > 0 {
+		b.offerLinks(m, urls)
+		return
+	}
+	b.send(newMessage(m.Chat.ID, "`I don't know this command. Enter /help or send me a link to save`"))
+}
+
+func (b *Bot) handleCallback(q *tgbotapi.CallbackQuery) {
+	b.touch(q.From.ID)
+	action := strings.SplitN(q.Data, ":", 2)[0]
+
+	switch action {
+	case forgetAction:
+		b.forgetCallback(q)
+	case againAction:
+		b.againCallback(q)
+	case teamAction:
+		b.teamCallback(q)
+	case linkAction:
+		b.linkCallback(q)
+	case randomAction:
+		b.randomCallback(q)
+	case readAction:
+		b.readCallback(q)
+	case remindAction:
+		b.remindCallback(q)
+	default:
+		b.answer(q, "")
+	}
+}
+
+// article runs user query, records it to user history and sends found articles to chat.
+// Every article gets a button to read it here, in group chats also a button to add it to the team list.
+func (b *Bot) article(chat *tgbotapi.Chat, userID int64, input string) {
+	if !devto.ValidateInput(input) {
+		b.send(newMessage(chat.ID, "`Enter the correct command!\n\n`"+descp))
+		return
+	}
+
+	query, err := devto.ParseInput(input)
+	if err != nil {
+		log.Print(err)
+		return
+	}
+	articles, err := b.devto.GetArticles(query.Tag, query.Freshness)
+	if err != nil {
+		log.Print(err)
+		return
+	}
+
+	allowed := b.moderate(chat.ID, *articles)
+	found := allowed.Head(query.Limit)
+	if err = b.archive.Put(found...); err != nil {
+		log.Print(err)
+	}
+	now := time.Now()
+	err = b.history.Add(userID, history.Entry{Query: input, Tag: query.Tag, Results: len(found), Time: now})
+	if err != nil {
+		log.Print(err)
+	}
+	if err = b.profiles.MarkSeen(userID, found, now); err != nil {
+		log.Print(err)
+	}
+	if query.Tag != "" {
+		b.feedback(userID, []string{query.Tag}, queryFeedback)
+	}
+
+	b.withGrowth(found)
+	msg := newMessage(chat.ID, found.WriteArticles(query.Limit))
+	switch {
+	case len(found) == 0:
+	case chat.IsGroup() || chat.IsSuperGroup():
+		keyboard := teamAddKeyboard(found)
+		for i, a := range found {
+			keyboard.InlineKeyboard[i] = append(keyboard.InlineKeyboard[i], tgbotapi.NewInlineKeyboardButtonData("📄", readData(a)))
+		}
+		msg.ReplyMarkup = keyboard
+	default:
+		msg.ReplyMarkup = readKeyboard(found)
+	}
+	b.send(msg)
+}
@@ -0,0 +1,157 @@
+package bot
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alebsys/telegram-article-bot/internal/archive"
+	"github.com/alebsys/telegram-article-bot/internal/chart"
+	"github.com/alebsys/telegram-article-bot/internal/devto"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	compareUsage       = "`Usage: /compare go rust zig 30`"
+	compareMaxTags     = 5
+	compareDefaultDays = 30
+	compareMaxDays     = 365
+	// compareTextWidth is the widest table still readable as text on a phone.
+	compareTextWidth = 64
+	compareTitle     = 40
+)
+
+var compareHeader = []string{"tag", "posts", "median", "top author", "top article"}
+
+// compare shows tags side by side: number of articles, median reactions, top author and article.
+func (b *Bot) compare(m *tgbotapi.Message) {
+	tags, days, err := parseCompareArgs(strings.Fields(m.CommandArguments()))
+	if err != nil {
+		b.send(newMessage(m.Chat.ID, "`"+err.Error()+"`\n"+compareUsage))
+		return
+	}
+
+	// fetch top articles of the period first, so the archive has them even if the crawler missed some
+	if _, err = b.topArticles(tags, days); err != nil {
+		log.Print(err)
+	}
+	from := time.Now().AddDate(0, 0, -days)
+	articles, err := b.archive.Since(from)
+	if err != nil {
+		log.Print(err)
+		return
+	}
+	articles = b.moderate(m.Chat.ID, articles)
+
+	var rows [][]string
+	var links, captions []string
+	for _, tag := range tags {
+		s := archive.Summarize(articles, tag, from)
+		row := []string{tag, strconv.Itoa(s.Count), strconv.FormatFloat(s.MedianReactions, 'f', -1, 64), "-", "-"}
+		if s.Count > 0 {
+			row[3] = "@" + s.TopAuthor
+			row[4] = truncate(s.TopArticle.Title, compareTitle)
+			links = append(links, devto.EscapeMarkdown(fmt.Sprintf("#%s %s", tag, s.TopArticle.Url)))
+			captions = append(captions, fmt.Sprintf("#%s %s\n%s", tag, row[4], s.TopArticle.Url))
+		}
+		rows = append(rows, row)
+	}
+	title := fmt.Sprintf("%s: last %d days", strings.Join(tags, " vs "), days)
+
+	if text := writeTable(compareHeader, rows); maxLineWidth(text) <= compareTextWidth {
+		b.send(newMessage(m.Chat.ID, "`"+title+"`\n```\n"+text+"```\n"+strings.Join(links, "\n")))
+		return
+	}
+
+	// the image font has ASCII glyphs only, so titles which may be in any language go to the caption
+	header := compareHeader[:len(compareHeader)-1]
+	for i := range rows {
+		rows[i] = rows[i][:len(header)]
+	}
+	buf := new(bytes.Buffer)
+	if err = (chart.Table{Title: title, Header: header, Rows: rows}).Render(buf); err != nil {
+		log.Print(err)
+		return
+	}
+	photo := tgbotapi.NewPhoto(m.Chat.ID, tgbotapi.FileBytes{Name: "compare.png", Bytes: buf.Bytes()})
+	photo.Caption = strings.Join(captions, "\n")
+	b.send(photo)
+}
+
+// parseCompareArgs parses tags and an optional number of days at the end.
+func parseCompareArgs(args []string) ([]string, int, error) {
+	days := compareDefaultDays
+	if n := len(args); n > 0 {
+		if d, err := strconv.Atoi(strings.TrimSuffix(args[n-1], "d")); err == nil {
+			if d < 1 || d > compareMaxDays {
+				return nil, 0, fmt.Errorf("period must be from 1 to %d days", compareMaxDays)
+			}
+			days = d
+			args = args[:n-1]
+		}
+	}
+
+	if len(args) == 0 || len(args) > compareMaxTags {
+		return nil, 0, fmt.Errorf("enter from 1 to %d tags", compareMaxTags)
+	}
+	tags := make([]string, 0, len(args))
+	for _, tag := range args {
+		tag = strings.ToLower(strings.TrimPrefix(tag, "#"))
+		if !tagRgxp.MatchString(tag) {
+			return nil, 0, fmt.Errorf("bad tag %q", tag)
+		}
+		tags = append(tags, tag)
+	}
+	return tags, days, nil
+}
+
+// writeTable aligns columns of rows with spaces, numbers are aligned to the right.
+func writeTable(header []string, rows [][]string) string {
+	widths := make([]int, len(header))
+	numeric := make([]bool, len(header))
+	for i, h := range header {
+		widths[i] = len([]rune(h))
+		numeric[i] = len(rows) > 0
+	}
+	for _, row := range rows {
+		for i, cell := range row {
+			if n := len([]rune(cell)); n > widths[i] {
+				widths[i] = n
+			}
+			if _, err := strconv.ParseFloat(cell, 64); err != nil {
+				numeric[i] = false
+			}
+		}
+	}
+
+	buf := new(bytes.Buffer)
+	for _, row := range append([][]string{header}, rows...) {
+		for i, cell := range row {
+			pad := strings.Repeat(" ", widths[i]-len([]rune(cell)))
+			if numeric[i] {
+				cell = pad + cell
+			} else if i < len(row)-1 {
+				cell += pad
+			}
+			if i > 0 {
+				buf.WriteString("  ")
+			}
+			buf.WriteString(cell)
+		}
+		buf.WriteString("\n")
+	}
+	return buf.String()
+}
+
+func maxLineWidth(text string) int {
+	var max int
+	for _, line := range strings.Split(text, "\n") {
+		if n := len([]rune(line)); n > max {
+			max = n
+		}
+	}
+	return max
+}
@@ -0,0 +1,235 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf16"
+
+	"github.com/alebsys/telegram-article-bot/internal/devto"
+	"github.com/alebsys/telegram-article-bot/internal/draft"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	draftTTL       = 7 * 24 * time.Hour
+	draftMaxTitle  = 128
+	draftMaxTags   = 4
+	draftTitleText = "`Send the title of the article. /cancel stops writing`"
+	draftTagsText  = "`Send up to 4 tags separated by commas or spaces, or - to skip`"
+	draftBodyText  = "`Send the body in Markdown, in as many messages as you need, forwarded ones too. /done creates the draft on DEV.TO, /cancel drops it`"
+)
+
+// startDraft starts a conversation which collects a new article of user.
+func (b *Bot) startDraft(m *tgbotapi.Message) {
+	if !m.Chat.IsPrivate() {
+		b.send(newMessage(m.Chat.ID, "`Write drafts in our private chat`"))
+		return
+	}
+	if b.accounts == nil {
+		b.send(newMessage(m.Chat.ID, linkDisabledText))
+		return
+	}
+	if _, ok, err := b.accounts.Get(m.From.ID); err != nil || !ok {
+		if err != nil {
+			log.Print(err)
+		}
+		b.send(newMessage(m.Chat.ID, noAccountText))
+		return
+	}
+
+	if err := b.drafts.Put(m.From.ID, draft.Draft{Step: draft.StepTitle, Started: time.Now()}); err != nil {
+		log.Print(err)
+		return
+	}
+	b.send(newMessage(m.Chat.ID, draftTitleText))
+}
+
+// draftText adds message to the draft of user and reports whether user writes a draft.
+func (b *Bot) draftText(m *tgbotapi.Message) bool {
+	d, ok, err := b.drafts.Get(m.From.ID)
+	if err != nil {
+		log.Print(err)
+		return false
+	}
+	if !ok {
+		return false
+	}
+
+	var reply string
+	switch d.Step {
+	case draft.StepTitle:
+		title := strings.TrimSpace(m.Text)
+		if title == "" || len([]rune(title)) > draftMaxTitle {
+			b.send(newMessage(m.Chat.ID, fmt.Sprintf("`The title must be from 1 to %d characters`", draftMaxTitle)))
+			return true
+		}
+		d.Title, d.Step, reply = title, draft.StepTags, draftTagsText
+	case draft.StepTags:
+		tags, err := parseDraftTags(m.Text)
+		if err != nil {
+			b.send(newMessage(m.Chat.ID, "`"+err.Error()+"`\n"+draftTagsText))
+			return true
+		}
+		d.Tags, d.Step, reply = tags, draft.StepBody, draftBodyText
+	case draft.StepBody:
+		text, entities := m.Text, m.Entities
+		if text == "" {
+			text, entities = m.Caption, m.CaptionEntities
+		}
+		if text == "" {
+			b.send(newMessage(m.Chat.ID, "`Only text can be added to the draft`"))
+			return true
+		}
+		d.Parts = append(d.Parts, toMarkdown(text, entities))
+		reply = fmt.Sprintf("`Added, the draft has %d parts. /done when finished`", len(d.Parts))
+	}
+
+	if err = b.drafts.Put(m.From.ID, d); err != nil {
+		log.Print(err)
+		return true
+	}
+	b.send(newMessage(m.Chat.ID, reply))
+	return true
+}
+
+// finishDraft creates unpublished article from the draft of user with the linked API key.
+func (b *Bot) finishDraft(m *tgbotapi.Message) {
+	d, ok, err := b.drafts.Get(m.From.ID)
+	if err != nil {
+		log.Print(err)
+		return
+	}
+	if !ok {
+		b.send(newMessage(m.Chat.ID, "`You are not writing a draft. Start one with /draft`"))
+		return
+	}
+	if d.Step != draft.StepBody || len(d.Parts) == 0 {
+		b.send(newMessage(m.Chat.ID, "`The draft has no body yet`"))
+		return
+	}
+	if b.accounts == nil {
+		b.send(newMessage(m.Chat.ID, linkDisabledText))
+		return
+	}
+	apiKey, ok, err := b.accounts.Key(m.From.ID)
+	if err != nil {
+		log.Print(err)
+		return
+	}
+	if !ok {
+		b.send(newMessage(m.Chat.ID, noAccountText))
+		return
+	}
+
+	article, err := b.devto.CreateArticle(apiKey.Reveal(), devto.NewArticle{Title: d.Title, BodyMarkdown: d.Markdown(), Tags: d.Tags})
+	if errors.Is(err, devto.ErrUnauthorized) {
+		b.send(newMessage(m.Chat.ID, revokedKeyText))
+		return
+	}
+	if err != nil {
+		log.Print(err)
+		b.send(newMessage(m.Chat.ID, "`DEV.TO didn't accept the draft, try /done again later`"))
+		return
+	}
+	if err = b.drafts.Delete(m.From.ID); err != nil {
+		log.Print(err)
+	}
+	b.send(newMessage(m.Chat.ID, "`The draft is saved on DEV.TO, edit and publish it there:`\n"+article.Url+"/edit"))
+}
+
+// cancelDraft drops the draft of user.
+func (b *Bot) cancelDraft(m *tgbotapi.Message) {
+	_, ok, err := b.drafts.Get(m.From.ID)
+	if err != nil {
+		log.Print(err)
+		return
+	}
+	if !ok {
+		b.send(newMessage(m.Chat.ID, "`There is no draft to cancel`"))
+		return
+	}
+	if err = b.drafts.Delete(m.From.ID); err != nil {
+		log.Print(err)
+		return
+	}
+	b.send(newMessage(m.Chat.ID, "`The draft is dropped`"))
+}
+
+// parseDraftTags parses tags separated by commas or spaces, "-" means no tags.
+func parseDraftTags(text string) ([]string, error) {
+	text = strings.TrimSpace(text)
+	if text == "-" {
+		return nil, nil
+	}
+	var tags []string
+	for _, tag := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool { return r == ',' || r == ' ' }) {
+		tag = strings.TrimPrefix(tag, "#")
+		if !tagRgxp.MatchString(tag) {
+			return nil, fmt.Errorf("bad tag %q", tag)
+		}
+		tags = append(tags, tag)
+	}
+	if len(tags) == 0 || len(tags) > draftMaxTags {
+		return nil, fmt.Errorf("enter from 1 to %d tags", draftMaxTags)
+	}
+	return tags, nil
+}
+
+// toMarkdown restores Markdown from formatting entities of Telegram message.
+// Offsets of entities are in UTF-16 code units.
+func toMarkdown(text string, entities []tgbotapi.MessageEntity) string {
+	if len(entities) == 0 {
+		return text
+	}
+	units := utf16.Encode([]rune(text))
+	opening := make(map[int][]string)
+	closing := make(map[int][]string)
+	for _, e := range entities {
+		var prefix, suffix string
+		switch e.Type {
+		case "bold":
+			prefix, suffix = "**", "**"
+		case "italic":
+			prefix, suffix = "_", "_"
+		case "strikethrough":
+			prefix, suffix = "~~", "~~"
+		case "code":
+			prefix, suffix = "`", "`"
+		case "pre":
+			prefix, suffix = "```"+e.Language+"\n", "\n```"
+		case "text_link":
+			prefix, suffix = "[", "]("+e.URL+")"
+		default:
+			continue
+		}
+		end := e.Offset + e.Length
+		if e.Offset < 0 || end > len(units) {
+			continue
+		}
+		opening[e.Offset] = append(opening[e.Offset], prefix)
+		// entities opened later are closed first
+		closing[end] = append([]string{suffix}, closing[end]...)
+	}
+
+	buf := new(strings.Builder)
+	var run []uint16
+	flush := func() {
+		buf.WriteString(string(utf16.Decode(run)))
+		run = run[:0]
+	}
+	for i := 0; i <= len(units); i++ {
+		if len(closing[i]) > 0 || len(opening[i]) > 0 {
+			flush()
+			buf.WriteString(strings.Join(closing[i], ""))
+			buf.WriteString(strings.Join(opening[i], ""))
+		}
+		if i < len(units) {
+			run = append(run, units[i])
+		}
+	}
+	flush()
+	return buf.String()
+}
@@ -0,0 +1,125 @@
+package bot
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alebsys/telegram-article-bot/internal/history"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	againAction  = "again"
+	historyShown = 10
+	statsTopTags = 5
+)
+
+// showHistory lists recent queries of user with buttons to run them again.
+func (b *Bot) showHistory(m *tgbotapi.Message) {
+	entries, err := b.history.List(m.From.ID)
+	if err != nil {
+		log.Print(err)
+		return
+	}
+	if len(entries) == 0 {
+		b.send(newMessage(m.Chat.ID, "`Your history is empty`"))
+		return
+	}
+	if len(entries) > historyShown {
+		entries = entries[len(entries)-historyShown:]
+	}
+
+	buf := new(bytes.Buffer)
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+		n := len(entries) - i
+		// the list is a code entity, which ends at the first backtick
+		query := strings.ReplaceAll(e.Query, "`", "'")
+		buf.WriteString(fmt.Sprintf("%d. %s - %d results, %s\n", n, query, e.Results, e.Time.UTC().Format("2006-01-02 15:04")))
+
+		data := againAction + ":" + strconv.Itoa(e.ID)
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("%d. %s", n, e.Query), data),
+		))
+	}
+
+	msg := newMessage(m.Chat.ID, "`"+buf.String()+"`")
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(rows...)
+	b.send(msg)
+}
+
+// again runs the last query of user.
+func (b *Bot) again(m *tgbotapi.Message) {
+	e, ok, err := b.history.Last(m.From.ID)
+	if err != nil {
+		log.Print(err)
+		return
+	}
+	if !ok {
+		b.send(newMessage(m.Chat.ID, "`Your history is empty`"))
+		return
+	}
+	b.article(m.Chat, m.From.ID, e.Query)
+}
+
+// againCallback runs query from history picked with a button.
+func (b *Bot) againCallback(q *tgbotapi.CallbackQuery) {
+	b.answer(q, "")
+	if q.Message == nil {
+		return
+	}
+
+	id, err := strconv.Atoi(strings.TrimPrefix(q.Data, againAction+":"))
+	if err != nil {
+		log.Printf("bad callback data %q: %v", q.Data, err)
+		return
+	}
+	e, ok, err := b.history.Find(q.From.ID, id)
+	if err != nil {
+		log.Print(err)
+		return
+	}
+	if !ok {
+		b.send(newMessage(q.Message.Chat.ID, "`This query is not in your history anymore`"))
+		return
+	}
+	b.article(q.Message.Chat, q.From.ID, e.Query)
+}
+
+// stats shows how user searches for articles.
+func (b *Bot) stats(m *tgbotapi.Message) {
+	entries, err := b.history.List(m.From.ID)
+	if err != nil {
+		log.Print(err)
+		return
+	}
+	if len(entries) == 0 {
+		b.send(newMessage(m.Chat.ID, "`Your history is empty`"))
+		return
+	}
+
+	var results, week int
+	weekAgo := time.Now().Add(-7 * 24 * time.Hour)
+	for _, e := range entries {
+		results += e.Results
+		if e.Time.After(weekAgo) {
+			week++
+		}
+	}
+
+	buf := new(bytes.Buffer)
+	buf.WriteString(fmt.Sprintf("Queries: %d\nLast 7 days: %d\nArticles found: %d\n", len(entries), week, results))
+	if tags := history.TopTags(entries, statsTopTags); len(tags) > 0 {
+		buf.WriteString("\nFavourite tags:\n")
+		for _, t := range tags {
+			buf.WriteString(fmt.Sprintf("* %s - %d\n", t.Tag, t.Count))
+		}
+	}
+
+	b.send(newMessage(m.Chat.ID, "`"+buf.String()+"`"))
+}
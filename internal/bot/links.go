@@ -0,0 +1,256 @@
+package bot
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/alebsys/telegram-article-bot/internal/bookmarks"
+	"github.com/alebsys/telegram-article-bot/internal/devto"
+	"github.com/alebsys/telegram-article-bot/internal/link"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	linkAction    = "link"
+	maxOfferLinks = 3
+	similarLimit  = 5
+	similarDays   = 30
+	savedShown    = 20
+	noteShown     = 60
+)
+
+// offerLinks resolves links sent to the bot and offers to save, summarize or find similar articles.
+func (b *Bot) offerLinks(m *tgbotapi.Message, urls []string) {
+	if len(urls) > maxOfferLinks {
+		urls = urls[:maxOfferLinks]
+	}
+	for _, u := range urls {
+		l, err := b.resolver.Resolve(u)
+		if err != nil {
+			log.Print(err)
+			b.send(newMessage(m.Chat.ID, "`Failed to open "+u+"`"))
+			continue
+		}
+
+		id, err := b.pending.Put(m.From.ID, l)
+		if err != nil {
+			log.Print(err)
+			continue
+		}
+		msg := newMessage(m.Chat.ID, writeLink(l, false))
+		msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("💾 Save", linkAction+":save:"+id),
+			tgbotapi.NewInlineKeyboardButtonData("📝 Summarize", linkAction+":summary:"+id),
+			tgbotapi.NewInlineKeyboardButtonData("🔎 Similar", linkAction+":similar:"+id),
+		))
+		b.send(msg)
+	}
+}
+
+// linkCallback handles Save, Summarize and Similar buttons of an offered link.
+func (b *Bot) linkCallback(q *tgbotapi.CallbackQuery) {
+	parts := strings.Split(q.Data, ":")
+	if len(parts) != 3 || q.Message == nil {
+		b.answer(q, "")
+		return
+	}
+	l, ok, err := b.pending.Get(q.From.ID, parts[2])
+	if err != nil {
+		log.Print(err)
+	}
+	if !ok {
+		b.answer(q, "The link has expired, send it again")
+		return
+	}
+	chatID := q.Message.Chat.ID
+
+	switch parts[1] {
+	case "save":
+		bm, added, err := b.bookmarks.Add(q.From.ID, l, time.Now())
+		if err != nil {
+			log.Print(err)
+			b.answer(q, "Failed to save the link")
+			return
+		}
+		if added {
+			b.answer(q, fmt.Sprintf("Saved as #%d", bm.ID))
+			b.feedback(q.From.ID, l.Tags, saveFeedback)
+		} else {
+			b.answer(q, fmt.Sprintf("Already saved as #%d", bm.ID))
+		}
+
+		if err = b.bookmarks.Remember(q.From.ID, chatID, q.Message.MessageID, bm.ID); err != nil {
+			log.Print(err)
+			return
+		}
+		text := writeLink(l, false) + fmt.Sprintf("\n`Saved as #%d. Reply to this message with a note or #tags`", bm.ID)
+		edit := tgbotapi.NewEditMessageText(chatID, q.Message.MessageID, text)
+		edit.ParseMode = "markdown"
+		edit.DisableWebPagePreview = true
+		edit.ReplyMarkup = q.Message.ReplyMarkup
+		b.send(edit)
+	case "summary":
+		b.answer(q, "")
+		b.send(newMessage(chatID, writeLink(l, true)))
+	case "similar":
+		b.answer(q, "")
+		articles, err := b.similar(chatID, l)
+		if err != nil {
+			log.Print(err)
+			return
+		}
+		if len(articles) == 0 {
+			b.send(newMessage(chatID, "`No similar articles found`"))
+			return
+		}
+		b.withGrowth(articles)
+		b.send(newMessage(chatID, articles.WriteArticles(similarLimit)))
+	default:
+		b.answer(q, "")
+	}
+}
+
+// similar returns top DEV.TO articles sharing tags with the link which are not excluded in chat.
+func (b *Bot) similar(chatID int64, l link.Link) (devto.Articles, error) {
+	var tags []string
+	for _, t := range l.Tags {
+		t = strings.ToLower(strings.Join(strings.Fields(t), ""))
+		if tagRgxp.MatchString(t) {
+			tags = append(tags, t)
+		}
+		if len(tags) == 3 {
+			break
+		}
+	}
+	if len(tags) == 0 {
+		return nil, nil
+	}
+
+	articles, err := b.topArticles(tags, similarDays)
+	if err != nil {
+		return nil, err
+	}
+	similar := articles[:0]
+	for _, a := range b.moderate(chatID, articles) {
+		if a.Url != l.URL && a.ID != l.ArticleID {
+			similar = append(similar, a)
+		}
+	}
+	return similar.Head(similarLimit), nil
+}
+
+// saved lists bookmarks of user, '/saved #tag "text"' lists only bookmarks with the tag and text.
+func (b *Bot) saved(m *tgbotapi.Message) {
+	list, err := b.bookmarks.List(m.From.ID)
+	if err != nil {
+		log.Print(err)
+		return
+	}
+	if len(list) == 0 {
+		b.send(newMessage(m.Chat.ID, "`You have no saved articles. Send me a link to save it`"))
+		return
+	}
+	tags, text := parseSavedQuery(m.CommandArguments())
+	if list = bookmarks.Search(list, tags, text); len(list) == 0 {
+		b.send(newMessage(m.Chat.ID, "`Nothing found`"))
+		return
+	}
+
+	buf := new(bytes.Buffer)
+	for i := len(list) - 1; i >= 0 && i >= len(list)-savedShown; i-- {
+		bm := list[i]
+		buf.WriteString(fmt.Sprintf("`#%d` %s\n`  %s, %s`\n", bm.ID, devto.MarkdownLink(bm.Title, bm.URL), bm.State, bm.SavedAt.UTC().Format("2006-01-02")))
+		if len(bm.PersonalTags) > 0 {
+			buf.WriteString("`  #" + strings.Join(bm.PersonalTags, " #") + "`\n")
+		}
+		if bm.Note != "" {
+			buf.WriteString("`  📝 " + truncate(strings.Join(strings.Fields(bm.Note), " "), noteShown) + "`\n")
+		}
+		buf.WriteString("\n")
+	}
+	b.send(newMessage(m.Chat.ID, buf.String()))
+}
+
+// note attaches reply to a saved article message to the bookmark as a note with hashtags.
+// It reports false if the replied message does not show a bookmark.
+func (b *Bot) note(m *tgbotapi.Message) bool {
+	id, ok, err := b.bookmarks.ByMessage(m.From.ID, m.Chat.ID, m.ReplyToMessage.MessageID)
+	if err != nil {
+		log.Print(err)
+	}
+	if !ok {
+		return false
+	}
+
+	var tags []string
+	ok, err = b.bookmarks.Update(m.From.ID, id, func(bm *bookmarks.Bookmark) {
+		bm.AddNote(strings.Replace(m.Text, "`", "'", -1))
+		tags = bm.PersonalTags
+	})
+	if err != nil {
+		log.Print(err)
+		return true
+	}
+	if !ok {
+		b.send(newMessage(m.Chat.ID, "`This article is not saved anymore`"))
+		return true
+	}
+
+	text := fmt.Sprintf("`Note added to #%d`", id)
+	if len(tags) > 0 {
+		text = fmt.Sprintf("`Note added to #%d, tags: #%s`", id, strings.Join(tags, " #"))
+	}
+	b.send(newMessage(m.Chat.ID, text))
+	return true
+}
+
+// parseSavedQuery splits '/saved' arguments into hashtags and a search text, quotes around the text are optional.
+func parseSavedQuery(args string) ([]string, string) {
+	var tags, words []string
+	for _, w := range strings.Fields(args) {
+		if strings.HasPrefix(w, "#") && len(w) > 1 {
+			tags = append(tags, strings.ToLower(w))
+			continue
+		}
+		words = append(words, w)
+	}
+	text := strings.Join(words, " ")
+	text = strings.Trim(text, "\"“”«»")
+	return tags, strings.TrimSpace(text)
+}
+
+// writeLink makes a card of the link, summary adds its description.
+// Site, author and tags come from the page, so they are kept from ending the code entities early.
+func writeLink(l link.Link, summary bool) string {
+	buf := new(bytes.Buffer)
+	buf.WriteString(devto.MarkdownLink(l.Title, l.URL) + "\n")
+
+	var info []string
+	if l.Site != "" {
+		info = append(info, l.Site)
+	}
+	if l.Author != "" {
+		info = append(info, l.Author)
+	}
+	if l.ReadingTime > 0 {
+		info = append(info, fmt.Sprintf("%d min read", l.ReadingTime))
+	}
+	if len(info) > 0 {
+		buf.WriteString("`  " + devto.EscapeCode(strings.Join(info, " · ")) + "`\n")
+	}
+	if len(l.Tags) > 0 {
+		buf.WriteString("`  #" + devto.EscapeCode(strings.Join(l.Tags, " #")) + "`\n")
+	}
+
+	if summary {
+		if l.Description == "" {
+			buf.WriteString("\n`No summary available`\n")
+		} else {
+			buf.WriteString("\n" + devto.EscapeMarkdown(l.Description) + "\n")
+		}
+	}
+	return buf.String()
+}
@@ -0,0 +1,146 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/alebsys/telegram-article-bot/internal/devto"
+	"github.com/alebsys/telegram-article-bot/internal/link"
+	"github.com/alebsys/telegram-article-bot/internal/moderation"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	filterUsage = "`Usage:\n/filter add word|tag|author <values>\n/filter remove word|tag|author <values>\n/filter sfw on|off\n/filter clear`"
+	whyUsage    = "`Usage: /why <DEV.TO article URL>`"
+)
+
+// showFilter shows moderation rules of the chat or changes them, only administrators can change rules of a group.
+func (b *Bot) showFilter(m *tgbotapi.Message) {
+	args := strings.Fields(strings.ToLower(m.CommandArguments()))
+	if len(args) == 0 {
+		rules, err := b.moderation.Get(m.Chat.ID)
+		if err != nil {
+			log.Print(err)
+			return
+		}
+		b.send(newMessage(m.Chat.ID, "`"+writeRules(rules)+"`"))
+		return
+	}
+
+	op, kind, values, err := parseFilterArgs(args)
+	if err != nil {
+		b.send(newMessage(m.Chat.ID, "`"+err.Error()+"`\n"+filterUsage))
+		return
+	}
+	if !b.isAdmin(m.Chat, m.From.ID) {
+		b.send(newMessage(m.Chat.ID, "`Only administrators can change filters`"))
+		return
+	}
+
+	var before moderation.Rules
+	rules, err := b.moderation.Update(m.Chat.ID, func(r *moderation.Rules) {
+		before = r.Clone()
+		switch op {
+		case "add":
+			r.Add(kind, values...)
+		case "remove":
+			r.Remove(kind, values...)
+		case "sfw":
+			r.SFW = values[0] == "on"
+		case "clear":
+			*r = moderation.Rules{}
+		}
+	})
+	if err != nil {
+		log.Print(err)
+		return
+	}
+	b.record(m, "filter", before, rules)
+	b.send(newMessage(m.Chat.ID, "`"+writeRules(rules)+"`"))
+}
+
+// parseFilterArgs parses a change of moderation rules.
+func parseFilterArgs(args []string) (op, kind string, values []string, err error) {
+	op = args[0]
+	switch op {
+	case "add", "remove":
+		if len(args) < 3 {
+			return "", "", nil, fmt.Errorf("enter what to %s", op)
+		}
+		kind = args[1]
+		if kind != moderation.Word && kind != moderation.Tag && kind != moderation.Author {
+			return "", "", nil, fmt.Errorf("unknown filter %q", kind)
+		}
+		return op, kind, args[2:], nil
+	case "sfw":
+		if len(args) != 2 || (args[1] != "on" && args[1] != "off") {
+			return "", "", nil, fmt.Errorf("safe for work mode is on or off")
+		}
+		return op, "", args[1:], nil
+	case "clear":
+		if len(args) != 1 {
+			return "", "", nil, fmt.Errorf("too many arguments")
+		}
+		return op, "", nil, nil
+	}
+	return "", "", nil, fmt.Errorf("unknown action %q", op)
+}
+
+func writeRules(r moderation.Rules) string {
+	if r.Empty() {
+		return "No filters in this chat. Add them with /filter add"
+	}
+	list := func(prefix string, values []string) string {
+		if len(values) == 0 {
+			return "-"
+		}
+		result := make([]string, 0, len(values))
+		for _, v := range values {
+			result = append(result, prefix+v)
+		}
+		return strings.Join(result, ", ")
+	}
+	sfw := "off"
+	if r.SFW {
+		sfw = "on"
+	}
+	return fmt.Sprintf("Filters of this chat:\nwords: %s\ntags: %s\nauthors: %s\nsafe for work: %s",
+		list("", r.Words), list("#", r.Tags), list("@", r.Authors), sfw)
+}
+
+// why explains which moderation rule of the chat excludes a DEV.TO article.
+func (b *Bot) why(m *tgbotapi.Message) {
+	path, ok := link.DevtoPath(strings.TrimSpace(m.CommandArguments()))
+	if !ok {
+		b.send(newMessage(m.Chat.ID, whyUsage))
+		return
+	}
+	rules, err := b.moderation.Get(m.Chat.ID)
+	if err != nil {
+		log.Print(err)
+		return
+	}
+	article, err := b.devto.GetArticleByPath(path)
+	if err != nil {
+		log.Print(err)
+		b.send(newMessage(m.Chat.ID, "`Failed to get the article from DEV.TO`"))
+		return
+	}
+	if reason, excluded := rules.Check(*article); excluded {
+		b.send(newMessage(m.Chat.ID, fmt.Sprintf("`The article is hidden in this chat: %s`", reason)))
+		return
+	}
+	b.send(newMessage(m.Chat.ID, "`No filter of this chat excludes the article`"))
+}
+
+// moderate returns articles not excluded by moderation rules of chat.
+func (b *Bot) moderate(chatID int64, articles devto.Articles) devto.Articles {
+	rules, err := b.moderation.Get(chatID)
+	if err != nil {
+		log.Print(err)
+		return articles
+	}
+	return rules.Filter(articles)
+}
@@ -0,0 +1,339 @@
+package bot
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alebsys/telegram-article-bot/internal/devto"
+	"github.com/alebsys/telegram-article-bot/internal/poll"
+	"github.com/alebsys/telegram-article-bot/internal/scheduler"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	pollStartJob          = "poll_start"
+	pollCloseJob          = "poll_close"
+	pollQuestion          = "Article of the week?"
+	defaultPollCandidates = 5
+	defaultPollDuration   = 48 * time.Hour
+	maxPollOption         = 100
+	pollStatsShown        = 10
+	pollUsage             = "`Usage:\n/poll now 5 48 - start a poll of 5 top articles of the subscription closing in 48 hours;\n/poll weekly mon 10 5 48 - start such poll every Monday at 10:00 UTC;\n/poll off - stop weekly polls;\n/poll winners - previous winners;\n/poll stats - who votes.`"
+)
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// poll starts, schedules and reports "article of the week" polls depending on command arguments.
+func (b *Bot) poll(m *tgbotapi.Message) {
+	if !m.Chat.IsGroup() && !m.Chat.IsSuperGroup() {
+		b.send(newMessage(m.Chat.ID, "`Polls are available in group chats only`"))
+		return
+	}
+
+	args := strings.Fields(m.CommandArguments())
+	if len(args) == 0 {
+		b.send(newMessage(m.Chat.ID, pollUsage))
+		return
+	}
+
+	switch args[0] {
+	case "winners":
+		b.pollWinners(m.Chat.ID)
+		return
+	case "stats":
+		b.pollStats(m.Chat.ID)
+		return
+	}
+
+	if !b.isAdmin(m.Chat, m.From.ID) {
+		b.send(newMessage(m.Chat.ID, "`Only administrators can manage polls`"))
+		return
+	}
+
+	switch args[0] {
+	case "now":
+		n, d, err := parsePollArgs(args[1:])
+		if err != nil {
+			b.send(newMessage(m.Chat.ID, "`"+err.Error()+"\n\n`"+pollUsage))
+			return
+		}
+		if err = b.startPoll(m.Chat.ID, n, d); err != nil {
+			log.Print(err)
+			b.send(newMessage(m.Chat.ID, "`Failed to start the poll: "+err.Error()+"`"))
+		}
+	case "weekly":
+		b.schedulePoll(m, args[1:])
+	case "off":
+		s, ok, err := b.polls.Schedule(m.Chat.ID)
+		if err != nil {
+			log.Print(err)
+			return
+		}
+		if ok {
+			if err = b.scheduler.Cancel(s.JobID); err != nil {
+				log.Print(err)
+			}
+			if err = b.polls.DeleteSchedule(m.Chat.ID); err != nil {
+				log.Print(err)
+				return
+			}
+			b.record(m, "poll", s, nil)
+		}
+		b.send(newMessage(m.Chat.ID, "`Weekly polls are off`"))
+	default:
+		b.send(newMessage(m.Chat.ID, pollUsage))
+	}
+}
+
+// schedulePoll parses '/poll weekly' arguments and schedules the next poll of the chat.
+func (b *Bot) schedulePoll(m *tgbotapi.Message, args []string) {
+	chatID := m.Chat.ID
+	if len(args) < 2 {
+		b.send(newMessage(chatID, pollUsage))
+		return
+	}
+	weekday, ok := weekdays[strings.ToLower(args[0])]
+	hour, err := strconv.Atoi(args[1])
+	if !ok || err != nil || hour < 0 || hour > 23 {
+		b.send(newMessage(chatID, "`Day must be one of mon..sun and hour from 0 to 23\n\n`"+pollUsage))
+		return
+	}
+	n, d, err := parsePollArgs(args[2:])
+	if err != nil {
+		b.send(newMessage(chatID, "`"+err.Error()+"\n\n`"+pollUsage))
+		return
+	}
+
+	var before interface{}
+	if old, ok, _ := b.polls.Schedule(chatID); ok {
+		if err = b.scheduler.Cancel(old.JobID); err != nil {
+			log.Print(err)
+		}
+		before = old
+	}
+	s := poll.Schedule{ChatID: chatID, Weekday: weekday, Hour: hour, Candidates: n, Duration: d}
+	next := scheduler.NextWeekly(time.Now(), weekday, hour)
+	if s.JobID, err = b.scheduler.At(pollStartJob, next, chatID); err != nil {
+		log.Print(err)
+		return
+	}
+	if err = b.polls.SetSchedule(s); err != nil {
+		log.Print(err)
+		return
+	}
+	b.record(m, "poll", before, s)
+	b.send(newMessage(chatID, fmt.Sprintf("`Next poll starts %s`", next.Format("Mon, 02 Jan 15:04 UTC"))))
+}
+
+// parsePollArgs parses optional number of candidates and duration in hours.
+func parsePollArgs(args []string) (int, time.Duration, error) {
+	n, d := defaultPollCandidates, defaultPollDuration
+	if len(args) > 2 {
+		return 0, 0, fmt.Errorf("too many arguments")
+	}
+	if len(args) > 0 {
+		var err error
+		if n, err = strconv.Atoi(args[0]); err != nil || n < 2 || n > 10 {
+			return 0, 0, fmt.Errorf("number of candidates must be from 2 to 10")
+		}
+	}
+	if len(args) > 1 {
+		h, err := strconv.Atoi(args[1])
+		if err != nil || h < 1 || h > 24*7 {
+			return 0, 0, fmt.Errorf("duration must be from 1 to 168 hours")
+		}
+		d = time.Duration(h) * time.Hour
+	}
+	return n, d, nil
+}
+
+// startPoll sends a poll of n top articles of the chat subscription for the last week
+// and schedules its closing after d.
+func (b *Bot) startPoll(chatID int64, n int, d time.Duration) error {
+	sub, ok, err := b.subscriptions.Get(chatID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("the chat has no subscription, use /subscribe")
+	}
+	articles, err := b.topArticles(sub.Tags, 7)
+	if err != nil {
+		return err
+	}
+	articles = b.moderate(chatID, articles)
+	articles = articles.Head(n)
+	if len(articles) < 2 {
+		return fmt.Errorf("not enough articles for the poll")
+	}
+
+	buf := new(bytes.Buffer)
+	buf.WriteString("`Candidates for the article of the week:`\n\n")
+	candidates := make([]poll.Candidate, 0, len(articles))
+	options := make([]string, 0, len(articles))
+	for i, a := range articles {
+		buf.WriteString(fmt.Sprintf("%d. %s\n", i+1, devto.MarkdownLink(a.Title, a.Url)))
+		candidates = append(candidates, poll.Candidate{ArticleID: a.ID, Title: a.Title, URL: a.Url})
+		options = append(options, truncate(fmt.Sprintf("%d. %s", i+1, a.Title), maxPollOption))
+	}
+	b.send(newMessage(chatID, buf.String()))
+
+	cfg := tgbotapi.NewPoll(chatID, pollQuestion, options...)
+	cfg.IsAnonymous = false
+	sent, err := b.api.Send(cfg)
+	if err != nil {
+		return err
+	}
+	if sent.Poll == nil {
+		return fmt.Errorf("sent message has no poll")
+	}
+
+	deadline := time.Now().Add(d)
+	err = b.polls.Start(poll.Poll{
+		ID:         sent.Poll.ID,
+		ChatID:     chatID,
+		MessageID:  sent.MessageID,
+		Candidates: candidates,
+		Deadline:   deadline,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = b.scheduler.At(pollCloseJob, deadline, sent.Poll.ID)
+	return err
+}
+
+// startScheduledPoll starts the weekly poll of a chat and schedules the next one.
+func (b *Bot) startScheduledPoll(job scheduler.Job) error {
+	var chatID int64
+	if err := job.Decode(&chatID); err != nil {
+		return err
+	}
+	s, ok, err := b.polls.Schedule(chatID)
+	if err != nil || !ok || s.JobID != job.ID {
+		// the schedule has been turned off or replaced since the job was scheduled
+		return err
+	}
+
+	// the next poll is scheduled first, so a failure is retried before this poll starts
+	next := scheduler.NextWeekly(time.Now(), s.Weekday, s.Hour)
+	if s.JobID, err = b.scheduler.At(pollStartJob, next, chatID); err != nil {
+		return err
+	}
+	if err = b.polls.SetSchedule(s); err != nil {
+		return err
+	}
+
+	if err = b.startPoll(chatID, s.Candidates, s.Duration); err != nil {
+		log.Printf("weekly poll of %d: %v", chatID, err)
+	}
+	return nil
+}
+
+// closePoll stops the poll at its deadline and announces the winner.
+func (b *Bot) closePoll(job scheduler.Job) error {
+	var id string
+	if err := job.Decode(&id); err != nil {
+		return err
+	}
+	p, ok, err := b.polls.Get(id)
+	if err != nil || !ok {
+		return err
+	}
+
+	if _, err = b.api.StopPoll(tgbotapi.NewStopPoll(p.ChatID, p.MessageID)); err != nil {
+		log.Print(err)
+	}
+	// a closed poll returns the same winner, so a failed announcement is retried by the scheduler
+	winner, ok, err := b.polls.Close(id, time.Now())
+	if err != nil {
+		return err
+	}
+	text := "`Nobody voted for the article of the week`"
+	if ok {
+		text = fmt.Sprintf("🏆 Article of the week %s:\n%s\n`  Votes: %d`", winner.Week, devto.MarkdownLink(winner.Title, winner.URL), winner.Votes)
+	}
+	_, err = b.api.Send(newMessage(p.ChatID, text))
+	return err
+}
+
+// handlePollAnswer records an answer to a poll.
+func (b *Bot) handlePollAnswer(a *tgbotapi.PollAnswer) {
+	if _, err := b.polls.Answer(a.PollID, a.User.ID, a.OptionIDs, time.Now()); err != nil {
+		log.Print(err)
+	}
+}
+
+func (b *Bot) pollWinners(chatID int64) {
+	chat, err := b.polls.Chat(chatID)
+	if err != nil {
+		log.Print(err)
+		return
+	}
+	if len(chat.Winners) == 0 {
+		b.send(newMessage(chatID, "`No winners yet`"))
+		return
+	}
+
+	buf := new(bytes.Buffer)
+	for i := len(chat.Winners) - 1; i >= 0; i-- {
+		w := chat.Winners[i]
+		buf.WriteString(fmt.Sprintf("`%s` %s `- %d votes`\n", w.Week, devto.MarkdownLink(w.Title, w.URL), w.Votes))
+	}
+	b.send(newMessage(chatID, buf.String()))
+}
+
+func (b *Bot) pollStats(chatID int64) {
+	chat, err := b.polls.Chat(chatID)
+	if err != nil {
+		log.Print(err)
+		return
+	}
+	if len(chat.Stats) == 0 {
+		b.send(newMessage(chatID, "`Nobody has voted yet`"))
+		return
+	}
+
+	// every shown voter costs a request to Telegram, so only the top ones are shown
+	voters := chat.TopVoters()
+	buf := new(bytes.Buffer)
+	buf.WriteString("Polls voted:\n")
+	for i, user := range voters {
+		if i == pollStatsShown {
+			buf.WriteString(fmt.Sprintf("and %d more\n", len(voters)-i))
+			break
+		}
+		name := user
+		if id, err := strconv.ParseInt(user, 10, 64); err == nil {
+			name = b.memberName(chatID, id)
+		}
+		buf.WriteString(fmt.Sprintf("* %s - %d\n", devto.EscapeCode(name), chat.Stats[user].Polls))
+	}
+	b.send(newMessage(chatID, "`"+buf.String()+"`"))
+}
+
+// memberName returns username or first name of chat member, user id if the member is unknown.
+func (b *Bot) memberName(chatID, userID int64) string {
+	member, err := b.api.GetChatMember(tgbotapi.GetChatMemberConfig{
+		ChatConfigWithUser: tgbotapi.ChatConfigWithUser{ChatID: chatID, UserID: userID},
+	})
+	if err != nil || member.User == nil {
+		return strconv.FormatInt(userID, 10)
+	}
+	if member.User.UserName != "" {
+		return "@" + member.User.UserName
+	}
+	return member.User.FirstName
+}
@@ -0,0 +1,91 @@
+package bot
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const forgetAction = "forgetme"
+
+// UserData is implemented by every storage which keeps data about users.
+type UserData interface {
+	// Export returns everything stored about user.
+	Export(userID int64) (interface{}, error)
+	// Forget deletes everything stored about user.
+	Forget(userID int64) error
+}
+
+type userData struct {
+	name string
+	data UserData
+}
+
+// register adds storage to the list exported by /mydata and erased by /forgetme.
+func (b *Bot) register(name string, data UserData) {
+	b.userData = append(b.userData, userData{name: name, data: data})
+}
+
+// myData sends everything stored about user as a JSON document.
+func (b *Bot) myData(m *tgbotapi.Message) {
+	export := map[string]interface{}{
+		"user_id":     m.From.ID,
+		"exported_at": time.Now().UTC(),
+	}
+	for _, d := range b.userData {
+		v, err := d.data.Export(m.From.ID)
+		if err != nil {
+			log.Print(err)
+			b.send(newMessage(m.Chat.ID, "`Failed to export your data, try again later`"))
+			return
+		}
+		export[d.name] = v
+	}
+
+	data, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		log.Print(fmt.Errorf("error when marshal user data: %v", err))
+		return
+	}
+
+	doc := tgbotapi.NewDocument(m.Chat.ID, tgbotapi.FileBytes{Name: "mydata.json", Bytes: data})
+	doc.Caption = "Everything I store about you"
+	b.send(doc)
+}
+
+// forgetMe asks user to confirm deletion of the data.
+func (b *Bot) forgetMe(m *tgbotapi.Message) {
+	msg := newMessage(m.Chat.ID, "`All your data will be deleted except your administrative actions in the audit log. Are you sure?`")
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("Delete", forgetAction+":yes"),
+			tgbotapi.NewInlineKeyboardButtonData("Cancel", forgetAction+":no"),
+		),
+	)
+	b.send(msg)
+}
+
+// forgetCallback deletes user data from every storage once user has confirmed it.
+func (b *Bot) forgetCallback(q *tgbotapi.CallbackQuery) {
+	text := "`Deletion cancelled`"
+	if q.Data == forgetAction+":yes" {
+		text = "`Your data has been deleted`"
+		for _, d := range b.userData {
+			if err := d.data.Forget(q.From.ID); err != nil {
+				log.Print(err)
+				text = "`Failed to delete your data, try again later`"
+			}
+		}
+	}
+
+	b.answer(q, "")
+	if q.Message == nil {
+		return
+	}
+	edit := tgbotapi.NewEditMessageText(q.Message.Chat.ID, q.Message.MessageID, text)
+	edit.ParseMode = "markdown"
+	b.send(edit)
+}
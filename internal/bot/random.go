@@ -0,0 +1,162 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alebsys/telegram-article-bot/internal/devto"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	randomAction     = "random"
+	randomUsage      = "`Usage: /random [tag] [min_score]`"
+	randomMinScore   = 10
+	randomPages      = 10
+	randomPerPage    = 30
+	randomAnotherBtn = "🎲 Another"
+	// randomSample is how many archived candidates the weighted pick chooses from.
+	randomSample = 50
+)
+
+// random sends a well-received article the user has not seen yet, see sendRandom.
+func (b *Bot) random(m *tgbotapi.Message) {
+	tag, minScore, err := parseRandomArgs(strings.Fields(m.CommandArguments()))
+	if err != nil {
+		b.send(newMessage(m.Chat.ID, "`"+err.Error()+"`\n"+randomUsage))
+		return
+	}
+	b.sendRandom(m.Chat.ID, m.From.ID, tag, minScore)
+}
+
+// randomCallback sends one more random article with the same filters.
+func (b *Bot) randomCallback(q *tgbotapi.CallbackQuery) {
+	b.answer(q, "")
+	if q.Message == nil {
+		return
+	}
+	parts := strings.SplitN(q.Data, ":", 3)
+	if len(parts) != 3 {
+		log.Printf("bad callback data %q", q.Data)
+		return
+	}
+	minScore, err := strconv.Atoi(parts[2])
+	if err != nil {
+		log.Printf("bad callback data %q: %v", q.Data, err)
+		return
+	}
+	b.sendRandom(q.Message.Chat.ID, q.From.ID, parts[1], minScore)
+}
+
+// sendRandom picks an article with tag and at least minScore reactions from the archive,
+// or from a random page of DEV.TO results if the archive has nothing the user has not seen.
+// Articles with more reactions are picked more often.
+func (b *Bot) sendRandom(chatID, userID int64, tag string, minScore int) {
+	prof, err := b.profiles.Get(userID)
+	if err != nil {
+		log.Print(err)
+		return
+	}
+	rules, err := b.moderation.Get(chatID)
+	if err != nil {
+		log.Print(err)
+	}
+	keep := func(a devto.Article) bool {
+		if _, excluded := rules.Check(a); excluded {
+			return false
+		}
+		return a.Score >= minScore && (tag == "" || a.Tags.Has(tag)) && !prof.HasSeen(a.ID)
+	}
+	filter := func(articles devto.Articles) devto.Articles {
+		var result devto.Articles
+		for _, a := range articles {
+			if keep(a) {
+				result = append(result, a)
+			}
+		}
+		return result
+	}
+
+	candidates, err := b.archive.Sample(b.rand, randomSample, keep)
+	if err != nil {
+		log.Print(err)
+		return
+	}
+	if len(candidates) == 0 {
+		fetched, err := b.devto.GetPage(tag, 1+b.rand.Intn(randomPages), randomPerPage)
+		if err != nil {
+			log.Print(err)
+			return
+		}
+		if err = b.archive.Put(*fetched...); err != nil {
+			log.Print(err)
+		}
+		candidates = filter(*fetched)
+	}
+
+	a, ok := weightedPick(candidates, b.rand)
+	if !ok {
+		b.send(newMessage(chatID, "`Nothing new to discover, try another tag or a lower score`"))
+		return
+	}
+	if err = b.profiles.MarkSeen(userID, devto.Articles{a}, time.Now()); err != nil {
+		log.Print(err)
+	}
+
+	card := devto.Articles{a}
+	b.withGrowth(card)
+	msg := newMessage(chatID, card[0].WriteCard(true))
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(randomAnotherBtn, fmt.Sprintf("%s:%s:%d", randomAction, tag, minScore)),
+		tgbotapi.NewInlineKeyboardButtonData(readButton, readData(a)),
+	))
+	b.send(msg)
+}
+
+// parseRandomArgs parses an optional tag followed by an optional minimal score.
+func parseRandomArgs(args []string) (string, int, error) {
+	if len(args) > 2 {
+		return "", 0, fmt.Errorf("too many arguments")
+	}
+	var tag string
+	minScore := randomMinScore
+	for i, arg := range args {
+		if n, err := strconv.Atoi(arg); err == nil {
+			if n < 0 {
+				return "", 0, fmt.Errorf("score can't be negative")
+			}
+			minScore = n
+			continue
+		}
+		if i > 0 {
+			return "", 0, fmt.Errorf("score must be a number")
+		}
+		tag = strings.ToLower(strings.TrimPrefix(arg, "#"))
+		if !tagRgxp.MatchString(tag) {
+			return "", 0, fmt.Errorf("bad tag %q", tag)
+		}
+	}
+	return tag, minScore, nil
+}
+
+// weightedPick returns a random article, the chance of an article is proportional to its score plus one.
+func weightedPick(articles devto.Articles, r *rand.Rand) (devto.Article, bool) {
+	var total int
+	for _, a := range articles {
+		total += a.Score + 1
+	}
+	if total <= 0 {
+		return devto.Article{}, false
+	}
+	n := r.Intn(total)
+	for _, a := range articles {
+		if n -= a.Score + 1; n < 0 {
+			return a, true
+		}
+	}
+	return devto.Article{}, false
+}
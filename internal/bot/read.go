@@ -0,0 +1,86 @@
+package bot
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/alebsys/telegram-article-bot/internal/devto"
+	"github.com/alebsys/telegram-article-bot/internal/render"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	readAction = "read"
+	readButton = "📄 Read here"
+)
+
+// readCallback sends the article as an HTML document which can be read offline.
+func (b *Bot) readCallback(q *tgbotapi.CallbackQuery) {
+	if q.Message == nil {
+		b.answer(q, "")
+		return
+	}
+	id, err := strconv.Atoi(strings.TrimPrefix(q.Data, readAction+":"))
+	if err != nil {
+		log.Printf("bad callback data %q: %v", q.Data, err)
+		b.answer(q, "")
+		return
+	}
+
+	article, err := b.devto.GetArticleBody(id)
+	if errors.Is(err, devto.ErrNotFound) {
+		b.answer(q, "The article is not on DEV.TO anymore")
+		return
+	}
+	if err != nil {
+		log.Print(err)
+		b.answer(q, "Failed to get the article, try again later")
+		return
+	}
+	buf := new(bytes.Buffer)
+	if err = render.Article(buf, article.Article, article.BodyMarkdown); err != nil {
+		log.Print(err)
+		b.answer(q, "Failed to render the article")
+		return
+	}
+	b.answer(q, "")
+
+	doc := tgbotapi.NewDocument(q.Message.Chat.ID, tgbotapi.FileBytes{Name: documentName(article.Article), Bytes: buf.Bytes()})
+	doc.Caption = article.Title
+	b.send(doc)
+}
+
+// documentName returns name of HTML document of article made of its slug.
+func documentName(a devto.Article) string {
+	slug := path.Base(strings.Trim(a.Path, "/"))
+	if slug == "." || slug == "" {
+		slug = "article-" + strconv.Itoa(a.ID)
+	}
+	return slug + ".html"
+}
+
+// readData makes callback data of the read button of article.
+func readData(a devto.Article) string {
+	return fmt.Sprintf("%s:%d", readAction, a.ID)
+}
+
+// readKeyboard makes read button for every article, a single article gets a plain one.
+func readKeyboard(articles devto.Articles) tgbotapi.InlineKeyboardMarkup {
+	if len(articles) == 1 {
+		return tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(readButton, readData(articles[0])),
+		))
+	}
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0, len(articles))
+	for _, a := range articles {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("📄 "+truncate(a.Title, buttonTitle), readData(a)),
+		))
+	}
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
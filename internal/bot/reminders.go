@@ -0,0 +1,181 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alebsys/telegram-article-bot/internal/bookmarks"
+	"github.com/alebsys/telegram-article-bot/internal/reminder"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	remindAction     = "remind"
+	maxDailyReminder = 5
+)
+
+// sendReminders resurfaces unread bookmarks of users who turned reminders on.
+func (b *Bot) sendReminders(now time.Time) {
+	users, err := b.bookmarks.Users()
+	if err != nil {
+		log.Print(err)
+		return
+	}
+	for _, userID := range users {
+		if err := b.remind(userID, now); err != nil {
+			log.Printf("reminders of %d: %v", userID, err)
+		}
+	}
+}
+
+// remind sends due reminders to user if it is time according to user settings.
+func (b *Bot) remind(userID int64, now time.Time) error {
+	values, err := b.settings.All(userID)
+	if err != nil {
+		return err
+	}
+	if values[remindersSetting] != "on" {
+		return nil
+	}
+	schedule, err := reminderSchedule(values)
+	if err != nil {
+		return err
+	}
+	lastSent, err := b.reminders.LastSent(userID)
+	if err != nil {
+		return err
+	}
+	if !schedule.Due(now, lastSent) {
+		return nil
+	}
+
+	list, err := b.bookmarks.List(userID)
+	if err != nil {
+		return err
+	}
+	sent := 0
+	for _, bm := range list {
+		if sent == maxDailyReminder {
+			break
+		}
+		if !bm.ReminderDue(now) {
+			continue
+		}
+
+		msg := newMessage(userID, "⏰ `Still want to read it?`\n\n"+writeLink(bm.Link, false))
+		msg.ReplyMarkup = remindKeyboard(bm.ID)
+		reply, err := b.api.Send(msg)
+		if err != nil {
+			return err
+		}
+		sent++
+		if err = b.bookmarks.Remember(userID, userID, reply.MessageID, bm.ID); err != nil {
+			log.Print(err)
+		}
+
+		_, err = b.bookmarks.Update(userID, bm.ID, func(bm *bookmarks.Bookmark) {
+			bm.Reminded++
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return b.reminders.Sent(userID, now)
+}
+
+// remindCallback handles Read, Snooze and Drop buttons of a reminder.
+func (b *Bot) remindCallback(q *tgbotapi.CallbackQuery) {
+	parts := strings.Split(q.Data, ":")
+	if len(parts) != 3 || q.Message == nil {
+		b.answer(q, "")
+		return
+	}
+	id, err := strconv.Atoi(parts[2])
+	if err != nil {
+		b.answer(q, "")
+		return
+	}
+
+	bm, _, err := b.bookmarks.Get(q.From.ID, id)
+	if err != nil {
+		log.Print(err)
+	}
+
+	var text string
+	switch parts[1] {
+	case "read":
+		_, err = b.bookmarks.Update(q.From.ID, id, func(bm *bookmarks.Bookmark) {
+			bm.State = bookmarks.StateRead
+		})
+		text = "✅ Marked as read"
+		b.feedback(q.From.ID, bm.Tags, readFeedback)
+	case "snooze":
+		err = b.snooze(q.From.ID, id, time.Now())
+		text = "⏰ Snoozed until tomorrow"
+	case "drop":
+		err = b.bookmarks.Delete(q.From.ID, id)
+		text = "🗑 Dropped"
+		b.feedback(q.From.ID, bm.Tags, dropFeedback)
+	default:
+		b.answer(q, "")
+		return
+	}
+	if err != nil {
+		log.Print(err)
+		b.answer(q, "Failed to update the bookmark")
+		return
+	}
+	b.answer(q, text)
+
+	edit := tgbotapi.NewEditMessageReplyMarkup(q.Message.Chat.ID, q.Message.MessageID,
+		tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(text, remindAction+":done:"+parts[2]),
+		)),
+	)
+	b.send(edit)
+}
+
+// snooze postpones the reminder of bookmark until the next reminders of user, so they bring it back.
+func (b *Bot) snooze(userID int64, id int, now time.Time) error {
+	values, err := b.settings.All(userID)
+	if err != nil {
+		return err
+	}
+	schedule, err := reminderSchedule(values)
+	if err != nil {
+		return err
+	}
+	_, err = b.bookmarks.Update(userID, id, func(bm *bookmarks.Bookmark) {
+		bm.Snooze(schedule.Next(now))
+	})
+	return err
+}
+
+func remindKeyboard(id int) tgbotapi.InlineKeyboardMarkup {
+	data := fmt.Sprintf(":%d", id)
+	return tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("✅ Read", remindAction+":read"+data),
+		tgbotapi.NewInlineKeyboardButtonData("⏰ Snooze", remindAction+":snooze"+data),
+		tgbotapi.NewInlineKeyboardButtonData("🗑 Drop", remindAction+":drop"+data),
+	))
+}
+
+// reminderSchedule makes reminder schedule from user settings.
+func reminderSchedule(values map[string]string) (reminder.Schedule, error) {
+	at, err := reminder.ParseClock(values[remindAtSetting])
+	if err != nil {
+		return reminder.Schedule{}, err
+	}
+	quiet, err := reminder.ParseQuiet(values[quietHoursSetting])
+	if err != nil {
+		return reminder.Schedule{}, err
+	}
+	loc, err := time.LoadLocation(values[timezoneSetting])
+	if err != nil {
+		return reminder.Schedule{}, err
+	}
+	return reminder.Schedule{At: at, Quiet: quiet, Location: loc}, nil
+}
@@ -0,0 +1,104 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/alebsys/telegram-article-bot/internal/devto"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	risingUsage     = "`Usage: /rising [tag]`"
+	risingDays      = 3
+	risingMinGrowth = 5
+	risingShown     = 10
+	// sampleRetention keeps a week of samples, longer than /rising and the badges look back
+	sampleRetention = 7 * 24 * time.Hour
+	// sampleBatch limits how many archived articles are fetched by one resample
+	sampleBatch = 100
+)
+
+// resample fetches current reactions of archived articles published during the last days, so their growth
+// is tracked after they leave the latest articles of the crawler. Articles are taken by id in batches of
+// sampleBatch, every run continues after the last id of the previous one. Articles and samples are saved at once.
+func (b *Bot) resample(now time.Time) {
+	recent, err := b.archive.Since(now.AddDate(0, 0, -risingDays))
+	if err != nil {
+		log.Print(err)
+		return
+	}
+	sort.Slice(recent, func(i, j int) bool {
+		return recent[i].ID < recent[j].ID
+	})
+	start := sort.Search(len(recent), func(i int) bool { return recent[i].ID > b.sampleCursor })
+
+	var sampled devto.Articles
+	for i := 0; i < len(recent) && i < sampleBatch; i++ {
+		id := recent[(start+i)%len(recent)].ID
+		b.sampleCursor = id
+		article, err := b.devto.GetArticle(id)
+		if err != nil {
+			log.Print(err)
+			continue
+		}
+		sampled = append(sampled, *article)
+	}
+	if err := b.archive.Put(sampled...); err != nil {
+		log.Print(err)
+		return
+	}
+	if err := b.archive.Record(sampled, now); err != nil {
+		log.Print(err)
+	}
+}
+
+// rising shows recent articles which got the most reactions during the last day.
+func (b *Bot) rising(m *tgbotapi.Message) {
+	args := strings.Fields(m.CommandArguments())
+	var tag string
+	if len(args) > 1 {
+		b.send(newMessage(m.Chat.ID, risingUsage))
+		return
+	}
+	if len(args) == 1 {
+		tag = strings.ToLower(strings.TrimPrefix(args[0], "#"))
+		if !tagRgxp.MatchString(tag) {
+			b.send(newMessage(m.Chat.ID, fmt.Sprintf("`bad tag %q`\n", tag)+risingUsage))
+			return
+		}
+	}
+
+	now := time.Now()
+	archived, err := b.archive.Since(now.AddDate(0, 0, -risingDays))
+	if err != nil {
+		log.Print(err)
+		return
+	}
+	var recent devto.Articles
+	for _, a := range b.moderate(m.Chat.ID, archived) {
+		if tag == "" || a.Tags.Has(tag) {
+			recent = append(recent, a)
+		}
+	}
+	rising, err := b.archive.Rising(recent, now, risingMinGrowth)
+	if err != nil {
+		log.Print(err)
+		return
+	}
+	if len(rising) == 0 {
+		b.send(newMessage(m.Chat.ID, "`Nothing is rising yet, reactions are sampled every hour`"))
+		return
+	}
+	b.send(newMessage(m.Chat.ID, "`Rising today:`\n\n"+rising.WriteArticles(risingShown)))
+}
+
+// withGrowth sets growth of reactions of articles during the last day, see archive.Archive.SetGrowth.
+func (b *Bot) withGrowth(articles devto.Articles) {
+	if err := b.archive.SetGrowth(articles, time.Now()); err != nil {
+		log.Print(err)
+	}
+}
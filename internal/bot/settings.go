@@ -0,0 +1,118 @@
+package bot
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/alebsys/telegram-article-bot/internal/reminder"
+	"github.com/alebsys/telegram-article-bot/internal/settings"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	unfurlSetting = "unfurl"
+	unfurlOff     = "off"
+	unfurlOn      = "on"
+	unfurlSummary = "summary"
+
+	remindersSetting  = "reminders"
+	remindAtSetting   = "remind_at"
+	quietHoursSetting = "quiet_hours"
+	timezoneSetting   = "timezone"
+	trendingSetting   = "trending_report"
+)
+
+var knownSettings = []settings.Setting{
+	{
+		Name:        unfurlSetting,
+		Description: "cards for DEV.TO links posted in the group, summary adds article description",
+		Default:     unfurlOff,
+		Values:      []string{unfurlOff, unfurlOn, unfurlSummary},
+	},
+	{
+		Name:        trendingSetting,
+		Description: "weekly trending tags report for subscribed chats, Mondays 09:00 UTC",
+		Default:     "off",
+		Values:      []string{"off", "on"},
+	},
+	{
+		Name:        remindersSetting,
+		Description: "remind about unread saved articles 1, 7 and 30 days after saving",
+		Default:     "off",
+		Values:      []string{"off", "on"},
+	},
+	{
+		Name:        remindAtSetting,
+		Description: "time of reminders, HH:MM",
+		Default:     "09:00",
+		Validate: func(v string) error {
+			_, err := reminder.ParseClock(v)
+			return err
+		},
+	},
+	{
+		Name:        quietHoursSetting,
+		Description: "hours without reminders, e.g. 22-08, or off",
+		Default:     "off",
+		Validate: func(v string) error {
+			_, err := reminder.ParseQuiet(v)
+			return err
+		},
+	},
+	{
+		Name:        timezoneSetting,
+		Description: "time zone of reminders, e.g. Europe/Berlin",
+		Default:     "UTC",
+		Validate: func(v string) error {
+			if _, err := time.LoadLocation(v); err != nil {
+				return fmt.Errorf("unknown time zone %q", v)
+			}
+			return nil
+		},
+	},
+}
+
+// showSettings lists settings of the chat.
+func (b *Bot) showSettings(m *tgbotapi.Message) {
+	values, err := b.settings.All(m.Chat.ID)
+	if err != nil {
+		log.Print(err)
+		return
+	}
+
+	buf := new(bytes.Buffer)
+	buf.WriteString("Settings:\n")
+	for _, k := range b.settings.Known() {
+		buf.WriteString(fmt.Sprintf("* %s = %s - %s\n", k.Name, values[k.Name], k.Description))
+	}
+	buf.WriteString("\nChange with /set <name> <value>")
+	b.send(newMessage(m.Chat.ID, "`"+buf.String()+"`"))
+}
+
+// set changes a setting of the chat, only administrators can change settings of a group.
+func (b *Bot) set(m *tgbotapi.Message) {
+	args := strings.Fields(m.CommandArguments())
+	if len(args) != 2 {
+		b.send(newMessage(m.Chat.ID, "`Usage: /set <name> <value>, see /settings`"))
+		return
+	}
+	if !b.isAdmin(m.Chat, m.From.ID) {
+		b.send(newMessage(m.Chat.ID, "`Only administrators can change settings`"))
+		return
+	}
+
+	before, err := b.settings.Get(m.Chat.ID, args[0])
+	if err != nil {
+		b.send(newMessage(m.Chat.ID, "`"+err.Error()+"`"))
+		return
+	}
+	if err = b.settings.Set(m.Chat.ID, args[0], args[1]); err != nil {
+		b.send(newMessage(m.Chat.ID, "`"+err.Error()+"`"))
+		return
+	}
+	b.record(m, "set", map[string]string{args[0]: before}, map[string]string{args[0]: args[1]})
+	b.send(newMessage(m.Chat.ID, fmt.Sprintf("`%s = %s`", args[0], args[1])))
+}
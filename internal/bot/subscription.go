@@ -0,0 +1,219 @@
+package bot
+
+import (
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alebsys/telegram-article-bot/internal/devto"
+	"github.com/alebsys/telegram-article-bot/internal/subscription"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	defaultDigestLimit = 5
+	defaultDigestHour  = 9
+	maxDigestLimit     = subscription.MaxPicks
+	maxDigestMinutes   = 240
+	subscribeUsage     = "`Usage:\n/subscribe go,rust 5 9\nwhere:\n* go,rust - tags;\n* 5 - number of posts, or 30m - minutes of reading;\n* 9 - hour (UTC) of the daily digest.`"
+	// digestPart is how many articles of a digest are sent in one message,
+	// so the message fits into 4096 characters and its keyboard stays short.
+	digestPart = 10
+)
+
+var tagRgxp = regexp.MustCompile(`^[a-z0-9]+$`)
+
+// subscribe shows or replaces subscription of the chat.
+func (b *Bot) subscribe(m *tgbotapi.Message) {
+	args := strings.Fields(m.CommandArguments())
+	if len(args) == 0 {
+		sub, ok, err := b.subscriptions.Get(m.Chat.ID)
+		if err != nil {
+			log.Print(err)
+			return
+		}
+		if !ok {
+			b.send(newMessage(m.Chat.ID, "`The chat has no subscription.\n\n`"+subscribeUsage))
+			return
+		}
+		b.send(newMessage(m.Chat.ID, "`"+writeSubscription(sub)+"`"))
+		return
+	}
+	if !b.isAdmin(m.Chat, m.From.ID) {
+		b.send(newMessage(m.Chat.ID, "`Only administrators can change the subscription`"))
+		return
+	}
+
+	sub, err := parseSubscription(m.Chat.ID, args)
+	if err != nil {
+		b.send(newMessage(m.Chat.ID, "`"+err.Error()+"\n\n`"+subscribeUsage))
+		return
+	}
+	var before interface{}
+	if old, ok, _ := b.subscriptions.Get(m.Chat.ID); ok {
+		sub.LastSent = old.LastSent
+		before = old
+	}
+	if err = b.subscriptions.Put(sub); err != nil {
+		log.Print(err)
+		return
+	}
+	b.record(m, "subscribe", before, sub)
+	b.send(newMessage(m.Chat.ID, "`"+writeSubscription(sub)+"`"))
+}
+
+// unsubscribe removes subscription of the chat.
+func (b *Bot) unsubscribe(m *tgbotapi.Message) {
+	if !b.isAdmin(m.Chat, m.From.ID) {
+		b.send(newMessage(m.Chat.ID, "`Only administrators can change the subscription`"))
+		return
+	}
+	old, ok, err := b.subscriptions.Get(m.Chat.ID)
+	if err != nil {
+		log.Print(err)
+		return
+	}
+	if err = b.subscriptions.Delete(m.Chat.ID); err != nil {
+		log.Print(err)
+		return
+	}
+	if ok {
+		b.record(m, "unsubscribe", old, nil)
+	}
+	b.send(newMessage(m.Chat.ID, "`Unsubscribed`"))
+}
+
+// writeSubscription describes subscription.
+func writeSubscription(sub subscription.Subscription) string {
+	size := fmt.Sprintf("%d posts", sub.Limit)
+	if sub.Minutes > 0 {
+		size = fmt.Sprintf("%d minutes of reading", sub.Minutes)
+	}
+	return fmt.Sprintf("Subscribed to %s, %s daily at %02d:00 UTC", strings.Join(sub.Tags, ", "), size, sub.Hour)
+}
+
+// parseSubscription makes subscription from '/subscribe' arguments: tags, optional limit or reading time and hour.
+func parseSubscription(chatID int64, args []string) (subscription.Subscription, error) {
+	sub := subscription.Subscription{ChatID: chatID, Limit: defaultDigestLimit, Hour: defaultDigestHour}
+	if len(args) > 3 {
+		return sub, fmt.Errorf("too many arguments")
+	}
+
+	for _, tag := range strings.Split(strings.ToLower(args[0]), ",") {
+		if tag == "" {
+			continue
+		}
+		if !tagRgxp.MatchString(tag) {
+			return sub, fmt.Errorf("bad tag %q", tag)
+		}
+		sub.Tags = append(sub.Tags, tag)
+	}
+	if len(sub.Tags) == 0 {
+		return sub, fmt.Errorf("no tags")
+	}
+
+	if len(args) > 1 && strings.HasSuffix(args[1], "m") {
+		n, err := strconv.Atoi(strings.TrimSuffix(args[1], "m"))
+		if err != nil || n < 1 || n > maxDigestMinutes {
+			return sub, fmt.Errorf("reading time must be from 1 to %d minutes", maxDigestMinutes)
+		}
+		sub.Limit, sub.Minutes = 0, n
+	} else if len(args) > 1 {
+		n, err := strconv.Atoi(args[1])
+		if err != nil || n < 1 || n > maxDigestLimit {
+			return sub, fmt.Errorf("number of posts must be from 1 to %d", maxDigestLimit)
+		}
+		sub.Limit = n
+	}
+	if len(args) > 2 {
+		h, err := strconv.Atoi(args[2])
+		if err != nil || h < 0 || h > 23 {
+			return sub, fmt.Errorf("hour must be from 0 to 23")
+		}
+		sub.Hour = h
+	}
+	return sub, nil
+}
+
+// sendDigests sends daily digest to every subscribed chat which has not got it today.
+func (b *Bot) sendDigests(now time.Time) {
+	subs, err := b.subscriptions.All()
+	if err != nil {
+		log.Print(err)
+		return
+	}
+	for _, sub := range subs {
+		if !sub.Due(now) {
+			continue
+		}
+		articles, err := b.topArticles(sub.Tags, 1)
+		if err != nil {
+			log.Print(err)
+			continue
+		}
+		digest := sub.Pick(b.moderate(sub.ChatID, articles))
+
+		if len(digest) == 0 {
+			b.send(newMessage(sub.ChatID, fmt.Sprintf("`Daily digest: %s`\n\n`No new articles today`", strings.Join(sub.Tags, ", "))))
+		}
+		b.withGrowth(digest)
+		header := fmt.Sprintf("`Daily digest: %s · %d min of reading`\n\n", strings.Join(sub.Tags, ", "), subscription.ReadingTime(digest))
+		for i := 0; i < len(digest); i += digestPart {
+			part := digest[i:]
+			if len(part) > digestPart {
+				part = part[:digestPart]
+			}
+			msg := newMessage(sub.ChatID, header+part.WriteArticles(len(part)))
+			msg.ReplyMarkup = readKeyboard(part)
+			b.send(msg)
+			header = ""
+		}
+		if sub.ChatID > 0 {
+			// positive chat id is a private chat with the user
+			if err = b.profiles.MarkSeen(sub.ChatID, digest, now); err != nil {
+				log.Print(err)
+			}
+		}
+
+		sub.LastSent = now
+		if err = b.subscriptions.Put(sub); err != nil {
+			log.Print(err)
+		}
+	}
+}
+
+// topArticles fetches top articles of tags for the last days, archives them
+// and returns them sorted by score without duplicates.
+func (b *Bot) topArticles(tags []string, days int) (devto.Articles, error) {
+	if len(tags) == 0 {
+		tags = []string{""}
+	}
+
+	seen := make(map[int]bool)
+	var top devto.Articles
+	for _, tag := range tags {
+		articles, err := b.devto.GetArticles(tag, strconv.Itoa(days))
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range *articles {
+			if seen[a.ID] {
+				continue
+			}
+			seen[a.ID] = true
+			top = append(top, a)
+		}
+	}
+	sort.SliceStable(top, func(i, j int) bool {
+		return top[i].Score > top[j].Score
+	})
+
+	if err := b.archive.Put(top...); err != nil {
+		log.Print(err)
+	}
+	return top, nil
+}
@@ -0,0 +1,192 @@
+package bot
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alebsys/telegram-article-bot/internal/devto"
+	"github.com/alebsys/telegram-article-bot/internal/teamlist"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	teamAction    = "team"
+	buttonTitle   = 40
+	teamListEmpty = "`The team list is empty. Add articles with ➕ buttons under /article results`"
+)
+
+// teamList shows, closes or lists picks of the team list depending on command arguments.
+func (b *Bot) teamList(m *tgbotapi.Message) {
+	if !m.Chat.IsGroup() && !m.Chat.IsSuperGroup() {
+		b.send(newMessage(m.Chat.ID, "`Team lists are available in group chats only`"))
+		return
+	}
+
+	switch m.CommandArguments() {
+	case "":
+		items, err := b.teamLists.Ranked(m.Chat.ID)
+		if err != nil {
+			log.Print(err)
+			return
+		}
+		if len(items) == 0 {
+			b.send(newMessage(m.Chat.ID, teamListEmpty))
+			return
+		}
+		msg := newMessage(m.Chat.ID, writeTeamList(items))
+		msg.ReplyMarkup = teamVoteKeyboard(items)
+		b.send(msg)
+	case "close":
+		if !b.isAdmin(m.Chat, m.From.ID) {
+			b.send(newMessage(m.Chat.ID, "`Only administrators can close the team list`"))
+			return
+		}
+		pick, ok, err := b.teamLists.Close(m.Chat.ID, time.Now())
+		if err != nil {
+			log.Print(err)
+			return
+		}
+		if !ok {
+			b.send(newMessage(m.Chat.ID, teamListEmpty))
+			return
+		}
+		b.send(newMessage(m.Chat.ID, fmt.Sprintf("📚 Reading club pick of %s:\n%s\n`  Votes: %d`", pick.Week, devto.MarkdownLink(pick.Title, pick.URL), len(pick.Votes))))
+	case "picks":
+		picks, err := b.teamLists.Picks(m.Chat.ID)
+		if err != nil {
+			log.Print(err)
+			return
+		}
+		if len(picks) == 0 {
+			b.send(newMessage(m.Chat.ID, "`No reading club picks yet`"))
+			return
+		}
+		buf := new(bytes.Buffer)
+		for i := len(picks) - 1; i >= 0; i-- {
+			buf.WriteString(fmt.Sprintf("`%s` %s\n", picks[i].Week, devto.MarkdownLink(picks[i].Title, picks[i].URL)))
+		}
+		b.send(newMessage(m.Chat.ID, buf.String()))
+	default:
+		b.send(newMessage(m.Chat.ID, "`Usage:\n/teamlist - show the list;\n/teamlist close - pick the article of the week;\n/teamlist picks - previous picks.`"))
+	}
+}
+
+// teamCallback handles ➕ and vote buttons of team lists.
+func (b *Bot) teamCallback(q *tgbotapi.CallbackQuery) {
+	if q.Message == nil {
+		b.answer(q, "")
+		return
+	}
+
+	parts := strings.Split(q.Data, ":")
+	if len(parts) != 3 {
+		b.answer(q, "")
+		return
+	}
+	id, err := strconv.Atoi(parts[2])
+	if err != nil {
+		log.Printf("bad callback data %q: %v", q.Data, err)
+		b.answer(q, "")
+		return
+	}
+	chatID := q.Message.Chat.ID
+
+	switch parts[1] {
+	case "add":
+		article, ok, err := b.archive.Get(id)
+		if err != nil || !ok {
+			log.Printf("article %d for team list: found %v, %v", id, ok, err)
+			b.answer(q, "Article is not available anymore")
+			return
+		}
+		added, err := b.teamLists.Add(chatID, teamlist.Item{
+			ArticleID: article.ID,
+			Title:     article.Title,
+			URL:       article.Url,
+			AddedBy:   q.From.ID,
+			Added:     time.Now(),
+		})
+		if err != nil {
+			log.Print(err)
+			b.answer(q, "Failed to add the article")
+			return
+		}
+		if !added {
+			b.answer(q, "Already in the team list")
+			return
+		}
+		b.answer(q, "Added to the team list")
+	case "vote":
+		voted, err := b.teamLists.Vote(chatID, id, q.From.ID)
+		if err != nil {
+			log.Print(err)
+			b.answer(q, "Article is not in the team list anymore")
+			return
+		}
+		if voted {
+			b.answer(q, "Vote counted")
+		} else {
+			b.answer(q, "Vote removed")
+		}
+
+		items, err := b.teamLists.Ranked(chatID)
+		if err != nil {
+			log.Print(err)
+			return
+		}
+		edit := tgbotapi.NewEditMessageTextAndMarkup(chatID, q.Message.MessageID, writeTeamList(items), teamVoteKeyboard(items))
+		edit.ParseMode = "markdown"
+		edit.DisableWebPagePreview = true
+		b.send(edit)
+	default:
+		b.answer(q, "")
+	}
+}
+
+// teamAddKeyboard makes ➕ button for every article.
+func teamAddKeyboard(articles devto.Articles) tgbotapi.InlineKeyboardMarkup {
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0, len(articles))
+	for _, a := range articles {
+		data := fmt.Sprintf("%s:add:%d", teamAction, a.ID)
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("➕ "+truncate(a.Title, buttonTitle), data),
+		))
+	}
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// teamVoteKeyboard makes vote button for every item.
+func teamVoteKeyboard(items []teamlist.Item) tgbotapi.InlineKeyboardMarkup {
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0, len(items))
+	for _, item := range items {
+		text := fmt.Sprintf("👍 %d · %s", len(item.Votes), truncate(item.Title, buttonTitle))
+		data := fmt.Sprintf("%s:vote:%d", teamAction, item.ArticleID)
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(text, data)))
+	}
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+func writeTeamList(items []teamlist.Item) string {
+	if len(items) == 0 {
+		return teamListEmpty
+	}
+	buf := new(bytes.Buffer)
+	buf.WriteString("`Team reading list:`\n\n")
+	for i, item := range items {
+		buf.WriteString(fmt.Sprintf("%d. %s\n`  Votes: %d`\n\n", i+1, devto.MarkdownLink(item.Title, item.URL), len(item.Votes)))
+	}
+	return buf.String()
+}
+
+// truncate cuts s to n runes adding ellipsis.
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "…"
+}
@@ -0,0 +1,150 @@
+package bot
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/alebsys/telegram-article-bot/internal/archive"
+	"github.com/alebsys/telegram-article-bot/internal/devto"
+	"github.com/alebsys/telegram-article-bot/internal/scheduler"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	trendingJob      = "trending_report"
+	trendingWeekday  = time.Monday
+	trendingHour     = 9
+	trendingBaseline = 4
+	trendingMinCount = 3
+	trendingShown    = 10
+	crawlPages       = 3
+	crawlPerPage     = 100
+)
+
+// crawl archives the latest DEV.TO articles, so the archive has every article and not only requested ones.
+// Articles of all pages are archived at once.
+func (b *Bot) crawl(now time.Time) {
+	var crawled devto.Articles
+	for page := 1; page <= crawlPages; page++ {
+		articles, err := b.devto.GetLatest(page, crawlPerPage)
+		if err != nil {
+			log.Print(err)
+			break
+		}
+		crawled = append(crawled, *articles...)
+		if len(*articles) < crawlPerPage {
+			break
+		}
+	}
+	if err := b.archive.Put(crawled...); err != nil {
+		log.Print(err)
+		return
+	}
+	if err := b.archive.Record(crawled, now); err != nil {
+		log.Print(err)
+	}
+}
+
+// trending shows tags gaining volume compared with their baseline.
+func (b *Bot) trending(m *tgbotapi.Message) {
+	now := time.Now()
+	articles, err := b.trendingArticles(now)
+	if err != nil {
+		log.Print(err)
+		return
+	}
+	b.send(newMessage(m.Chat.ID, writeTrending(b.moderate(m.Chat.ID, articles), now)))
+}
+
+// trendingArticles returns archived articles of this week and the baseline weeks.
+func (b *Bot) trendingArticles(now time.Time) (devto.Articles, error) {
+	return b.archive.Since(now.Add(-(trendingBaseline + 1) * 7 * 24 * time.Hour))
+}
+
+// writeTrending makes the report of tags of articles which trend at now.
+func writeTrending(articles devto.Articles, now time.Time) string {
+	trends := archive.Trends(articles, now, trendingBaseline, trendingMinCount)
+	if len(trends) == 0 {
+		return "`Not enough articles in the archive yet`"
+	}
+	if len(trends) > trendingShown {
+		trends = trends[:trendingShown]
+	}
+
+	buf := new(bytes.Buffer)
+	buf.WriteString("`Trending tags, this week vs the previous one:`\n```\n")
+	buf.WriteString(fmt.Sprintf("%-14s %13s %15s\n", "tag", "posts", "reactions"))
+	for _, t := range trends {
+		buf.WriteString(fmt.Sprintf("%-14s %13s %15s\n",
+			truncate(t.Tag, 14),
+			fmt.Sprintf("%d %s", t.Count, writeDelta(t.Count, t.PrevCount)),
+			fmt.Sprintf("%d %s", t.Reactions, writeDelta(t.Reactions, t.PrevReactions)),
+		))
+	}
+	buf.WriteString("```")
+	return buf.String()
+}
+
+// scheduleTrendingReport schedules the next weekly trending report unless it is scheduled already.
+func (b *Bot) scheduleTrendingReport() error {
+	jobs, err := b.scheduler.Pending(trendingJob)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, job := range jobs {
+		// the running report is still pending until it succeeds
+		if job.At.After(now) {
+			return nil
+		}
+	}
+	_, err = b.scheduler.At(trendingJob, scheduler.NextWeekly(now, trendingWeekday, trendingHour), nil)
+	return err
+}
+
+// trendingReport sends trending tags to subscribed chats which turned the report on and schedules the next report.
+// Every chat gets the report of articles its moderation rules keep.
+func (b *Bot) trendingReport(scheduler.Job) error {
+	defer func() {
+		if err := b.scheduleTrendingReport(); err != nil {
+			log.Print(err)
+		}
+	}()
+
+	subs, err := b.subscriptions.All()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	var articles devto.Articles
+	var loaded bool
+	for _, sub := range subs {
+		on, err := b.settings.Get(sub.ChatID, trendingSetting)
+		if err != nil {
+			log.Print(err)
+			continue
+		}
+		if on != "on" {
+			continue
+		}
+		if !loaded {
+			if articles, err = b.trendingArticles(now); err != nil {
+				return err
+			}
+			loaded = true
+		}
+		b.send(newMessage(sub.ChatID, writeTrending(b.moderate(sub.ChatID, articles), now)))
+	}
+	return nil
+}
+
+// writeDelta formats relative change from prev to cur like '(+25%)'.
+func writeDelta(cur, prev int) string {
+	d, ok := archive.Delta(cur, prev)
+	if !ok {
+		return "(new)"
+	}
+	return fmt.Sprintf("(%+.0f%%)", d*100)
+}
@@ -0,0 +1,125 @@
+package bot
+
+import (
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alebsys/telegram-article-bot/internal/devto"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const (
+	unfurlChatWindow = 10 * time.Second
+	unfurlLinkWindow = time.Hour
+	maxUnfurlLinks   = 3
+)
+
+var devtoLinkRgxp = regexp.MustCompile(`https?://(?:www\.)?dev\.to/([A-Za-z0-9_]+/[A-Za-z0-9_\-]+)`)
+
+// unfurl replies with cards of DEV.TO articles linked in the message if the chat opted in.
+// A chat gets at most one reply per unfurlChatWindow and every article at most once per unfurlLinkWindow,
+// an article counts as shown once its card is sent.
+func (b *Bot) unfurl(m *tgbotapi.Message) {
+	paths := devtoPaths(messageText(m))
+	if len(paths) == 0 {
+		return
+	}
+
+	mode, err := b.settings.Get(m.Chat.ID, unfurlSetting)
+	if err != nil {
+		log.Print(err)
+		return
+	}
+	if mode == unfurlOff {
+		return
+	}
+
+	now := time.Now()
+	chat := strconv.FormatInt(m.Chat.ID, 10)
+	var fresh []string
+	for _, p := range paths {
+		if b.unfurlLinks.Ready(chat+"/"+p, now) {
+			fresh = append(fresh, p)
+		}
+	}
+	if len(fresh) == 0 || !b.unfurlChats.Allow(chat, now) {
+		return
+	}
+
+	rules, err := b.moderation.Get(m.Chat.ID)
+	if err != nil {
+		log.Print(err)
+		return
+	}
+	var shown devto.Articles
+	var shownPaths []string
+	for _, p := range fresh {
+		article, err := b.devto.GetArticleByPath(p)
+		if err != nil {
+			log.Print(err)
+			continue
+		}
+		if err = b.archive.Put(*article); err != nil {
+			log.Print(err)
+		}
+		if _, excluded := rules.Check(*article); excluded {
+			continue
+		}
+		shown = append(shown, *article)
+		shownPaths = append(shownPaths, p)
+	}
+	if len(shown) == 0 {
+		return
+	}
+
+	b.withGrowth(shown)
+	cards := make([]string, 0, len(shown))
+	for _, a := range shown {
+		cards = append(cards, a.WriteCard(mode == unfurlSummary))
+	}
+	msg := newMessage(m.Chat.ID, strings.Join(cards, "\n"))
+	msg.ReplyToMessageID = m.MessageID
+	msg.ReplyMarkup = readKeyboard(shown)
+	if _, err = b.api.Send(msg); err != nil {
+		log.Print(err)
+		return
+	}
+	for _, p := range shownPaths {
+		b.unfurlLinks.Allow(chat+"/"+p, now)
+	}
+}
+
+// messageText returns text or caption of message with URLs hidden behind text links.
+func messageText(m *tgbotapi.Message) string {
+	text, entities := m.Text, m.Entities
+	if text == "" {
+		text, entities = m.Caption, m.CaptionEntities
+	}
+	for _, e := range entities {
+		if e.Type == "text_link" {
+			text += " " + e.URL
+		}
+	}
+	return text
+}
+
+// devtoPaths returns unique 'username/slug' paths of DEV.TO links in text, at most maxUnfurlLinks.
+func devtoPaths(text string) []string {
+	var paths []string
+	seen := make(map[string]bool)
+	for _, match := range devtoLinkRgxp.FindAllStringSubmatch(text, -1) {
+		p := match[1]
+		if seen[p] || strings.HasPrefix(p, "t/") {
+			continue
+		}
+		seen[p] = true
+		paths = append(paths, p)
+		if len(paths) == maxUnfurlLinks {
+			break
+		}
+	}
+	return paths
+}
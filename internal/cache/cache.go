@@ -0,0 +1,121 @@
+package cache
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const defaultMaxEntries = 1000
+
+type entry struct {
+	status  int
+	header  http.Header
+	body    []byte
+	expires time.Time
+}
+
+// Cache keeps successful responses to GET requests for a while. One Cache can be shared by several Transports.
+type Cache struct {
+	mu         sync.Mutex
+	ttl        time.Duration
+	maxEntries int
+	entries    map[string]entry
+	now        func() time.Time
+}
+
+// New returns Cache which keeps responses for ttl and at most maxEntries of them, zero means 1000.
+func New(ttl time.Duration, maxEntries int) *Cache {
+	if maxEntries <= 0 {
+		maxEntries = defaultMaxEntries
+	}
+	return &Cache{ttl: ttl, maxEntries: maxEntries, entries: make(map[string]entry), now: time.Now}
+}
+
+func (c *Cache) get(key string) (entry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if ok && c.now().After(e.expires) {
+		delete(c.entries, key)
+		return e, false
+	}
+	return e, ok
+}
+
+func (c *Cache) put(key string, e entry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	if len(c.entries) >= c.maxEntries {
+		for k, old := range c.entries {
+			if now.After(old.expires) {
+				delete(c.entries, k)
+			}
+		}
+	}
+	for k := range c.entries {
+		if len(c.entries) < c.maxEntries {
+			break
+		}
+		delete(c.entries, k)
+	}
+	e.expires = now.Add(c.ttl)
+	c.entries[key] = e
+}
+
+// Transport is http.RoundTripper which answers GET requests from Cache.
+// Authorized requests are never cached.
+type Transport struct {
+	Cache *Cache
+	// Next makes requests missing in the cache, http.DefaultTransport if nil.
+	Next http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	next := t.Next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	if req.Method != http.MethodGet || req.Header.Get("api-key") != "" || req.Header.Get("Authorization") != "" {
+		return next.RoundTrip(req)
+	}
+
+	key := req.URL.String()
+	if e, ok := t.Cache.get(key); ok {
+		return e.response(req), nil
+	}
+
+	resp, err := next.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("error when reads response of %s: %v", key, err)
+	}
+	e := entry{status: resp.StatusCode, header: resp.Header.Clone(), body: body}
+	t.Cache.put(key, e)
+	return e.response(req), nil
+}
+
+func (e entry) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", e.status, http.StatusText(e.status)),
+		StatusCode:    e.status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        e.header.Clone(),
+		Body:          ioutil.NopCloser(bytes.NewReader(e.body)),
+		ContentLength: int64(len(e.body)),
+		Request:       req,
+	}
+}
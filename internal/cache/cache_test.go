@@ -0,0 +1,58 @@
+package cache
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestTransport(t *testing.T) {
+	hits := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.Write([]byte("articles"))
+	}))
+	defer srv.Close()
+
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	c := New(time.Minute, 0)
+	c.now = func() time.Time { return now }
+	// two bots share the cache
+	team := &http.Client{Transport: &Transport{Cache: c}}
+	public := &http.Client{Transport: &Transport{Cache: c}}
+
+	get := func(client *http.Client, apiKey string) string {
+		req, _ := http.NewRequest(http.MethodGet, srv.URL+"/articles?tag=go", nil)
+		if apiKey != "" {
+			req.Header.Set("api-key", apiKey)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("Transport: got error %v", err)
+		}
+		defer resp.Body.Close()
+		body, _ := ioutil.ReadAll(resp.Body)
+		return string(body)
+	}
+
+	cases := []struct {
+		name   string
+		client *http.Client
+		apiKey string
+		after  time.Duration
+		hits   int
+	}{
+		{"first request", team, "", 0, 1},
+		{"cached for another bot", public, "", 0, 1},
+		{"authorized", team, "s3cr3t", 0, 2},
+		{"expired", team, "", 2 * time.Minute, 3},
+	}
+	for _, c := range cases {
+		now = now.Add(c.after)
+		if body := get(c.client, c.apiKey); body != "articles" || hits != c.hits {
+			t.Errorf("Transport: %s; got %q, %d hits; want articles, %d hits", c.name, body, hits, c.hits)
+		}
+	}
+}
@@ -0,0 +1,216 @@
+package chart
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"math"
+)
+
+const (
+	width       = 1000
+	marginLeft  = 90
+	marginRight = 30
+	titleHeight = 50
+	panelHeight = 260
+	panelTitle  = 30
+	axisHeight  = 30
+	legendRow   = 30
+	gridLines   = 4
+)
+
+var (
+	background = color.RGBA{255, 255, 255, 255}
+	foreground = color.RGBA{40, 40, 40, 255}
+	grid       = color.RGBA{225, 225, 225, 255}
+	palette    = []color.RGBA{
+		{59, 73, 223, 255},
+		{220, 60, 60, 255},
+		{40, 160, 80, 255},
+		{230, 150, 30, 255},
+		{140, 70, 190, 255},
+		{20, 160, 170, 255},
+	}
+)
+
+// Series is a named line of values, one value per label of the chart.
+type Series struct {
+	Name   string
+	Values []float64
+}
+
+// Panel is a plot with its own Y axis sharing X axis with other panels of the chart.
+type Panel struct {
+	Title  string
+	Series []Series
+}
+
+// Chart is a line chart of one or more panels stacked vertically.
+type Chart struct {
+	Title  string
+	Labels []string
+	Panels []Panel
+}
+
+// Render draws chart as PNG image to w. Every series has to have a value for every label.
+func (c Chart) Render(w io.Writer) error {
+	if len(c.Labels) == 0 || len(c.Panels) == 0 {
+		return fmt.Errorf("chart has no data")
+	}
+	names := make(map[string]int)
+	var legend []string
+	for _, p := range c.Panels {
+		for _, s := range p.Series {
+			if len(s.Values) != len(c.Labels) {
+				return fmt.Errorf("series %s has %d values for %d labels", s.Name, len(s.Values), len(c.Labels))
+			}
+			if _, ok := names[s.Name]; !ok {
+				names[s.Name] = len(legend)
+				legend = append(legend, s.Name)
+			}
+		}
+	}
+
+	height := titleHeight + len(c.Panels)*(panelHeight+axisHeight) + legendRow
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), &image.Uniform{background}, image.Point{}, draw.Src)
+
+	drawText(img, (width-textWidth(c.Title))/2, 16, c.Title, foreground)
+
+	top := titleHeight
+	for _, p := range c.Panels {
+		plot := image.Rect(marginLeft, top+panelTitle, width-marginRight, top+panelHeight)
+		drawText(img, marginLeft, top+8, p.Title, foreground)
+		c.drawPanel(img, plot, p, names)
+		top += panelHeight + axisHeight
+	}
+
+	x := marginLeft
+	for i, name := range legend {
+		fill(img, image.Rect(x, top+2, x+14, top+16), palette[i%len(palette)])
+		drawText(img, x+20, top+2, name, foreground)
+		x += 20 + textWidth(name) + 30
+	}
+
+	return png.Encode(w, img)
+}
+
+func (c Chart) drawPanel(img *image.RGBA, plot image.Rectangle, p Panel, colors map[string]int) {
+	var max float64
+	for _, s := range p.Series {
+		for _, v := range s.Values {
+			max = math.Max(max, v)
+		}
+	}
+	max = niceMax(max)
+
+	for i := 0; i <= gridLines; i++ {
+		y := plot.Max.Y - i*plot.Dy()/gridLines
+		fill(img, image.Rect(plot.Min.X, y, plot.Max.X, y+1), grid)
+		label := formatValue(max * float64(i) / gridLines)
+		drawText(img, plot.Min.X-10-textWidth(label), y-glyphHeight, label, foreground)
+	}
+	fill(img, image.Rect(plot.Min.X, plot.Min.Y, plot.Min.X+1, plot.Max.Y), foreground)
+	fill(img, image.Rect(plot.Min.X, plot.Max.Y, plot.Max.X, plot.Max.Y+1), foreground)
+
+	n := len(c.Labels)
+	point := func(i int, v float64) image.Point {
+		x := plot.Min.X
+		if n > 1 {
+			x += i * plot.Dx() / (n - 1)
+		}
+		return image.Pt(x, plot.Max.Y-int(v/max*float64(plot.Dy())))
+	}
+
+	for _, i := range []int{0, n / 2, n - 1} {
+		label := c.Labels[i]
+		x := point(i, 0).X - textWidth(label)/2
+		if x < 0 {
+			x = 0
+		}
+		if x+textWidth(label) > width {
+			x = width - textWidth(label)
+		}
+		drawText(img, x, plot.Max.Y+8, label, foreground)
+	}
+
+	for _, s := range p.Series {
+		clr := palette[colors[s.Name]%len(palette)]
+		for i := 1; i < n; i++ {
+			line(img, point(i-1, s.Values[i-1]), point(i, s.Values[i]), clr)
+		}
+		if n == 1 {
+			pt := point(0, s.Values[0])
+			fill(img, image.Rect(pt.X-2, pt.Y-2, pt.X+3, pt.Y+3), clr)
+		}
+	}
+}
+
+// niceMax rounds max up to 1, 2 or 5 times a power of ten, so grid lines get round labels.
+func niceMax(max float64) float64 {
+	if max <= 0 {
+		return 1
+	}
+	pow := math.Pow(10, math.Floor(math.Log10(max)))
+	for _, m := range []float64{1, 2, 5, 10} {
+		if max <= m*pow {
+			return m * pow
+		}
+	}
+	return 10 * pow
+}
+
+func formatValue(v float64) string {
+	if v == math.Trunc(v) {
+		return fmt.Sprintf("%.0f", v)
+	}
+	return fmt.Sprintf("%.1f", v)
+}
+
+func fill(img *image.RGBA, r image.Rectangle, c color.Color) {
+	draw.Draw(img, r, &image.Uniform{c}, image.Point{}, draw.Src)
+}
+
+// line draws a two pixels thick line from a to b with Bresenham's algorithm.
+func line(img *image.RGBA, a, b image.Point, c color.RGBA) {
+	dx, dy := abs(b.X-a.X), -abs(b.Y-a.Y)
+	sx, sy := sign(b.X-a.X), sign(b.Y-a.Y)
+	e := dx + dy
+	for {
+		img.SetRGBA(a.X, a.Y, c)
+		img.SetRGBA(a.X+1, a.Y, c)
+		img.SetRGBA(a.X, a.Y+1, c)
+		if a == b {
+			return
+		}
+		e2 := 2 * e
+		if e2 >= dy {
+			e += dy
+			a.X += sx
+		}
+		if e2 <= dx {
+			e += dx
+			a.Y += sy
+		}
+	}
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+func sign(x int) int {
+	switch {
+	case x > 0:
+		return 1
+	case x < 0:
+		return -1
+	}
+	return 0
+}
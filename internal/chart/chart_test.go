@@ -0,0 +1,83 @@
+package chart
+
+import (
+	"bytes"
+	"image/png"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	c := Chart{
+		Title:  "go, rust: last 3 days",
+		Labels: []string{"10-14", "10-15", "10-16"},
+		Panels: []Panel{
+			{Title: "Articles per day", Series: []Series{{"go", []float64{1, 5, 3}}, {"rust", []float64{0, 2, 7}}}},
+			{Title: "Average reactions", Series: []Series{{"go", []float64{10, 0, 12.5}}, {"rust", []float64{3, 4, 5}}}},
+		},
+	}
+
+	buf := new(bytes.Buffer)
+	if err := c.Render(buf); err != nil {
+		t.Fatalf("Render: got error %v", err)
+	}
+	img, err := png.Decode(buf)
+	if err != nil {
+		t.Fatalf("Render: got invalid PNG %v", err)
+	}
+	if img.Bounds().Dx() != width || img.Bounds().Dy() != titleHeight+2*(panelHeight+axisHeight)+legendRow {
+		t.Errorf("Render: got size %v", img.Bounds())
+	}
+
+	bad := c
+	bad.Panels = []Panel{{Series: []Series{{"go", []float64{1}}}}}
+	if err = bad.Render(new(bytes.Buffer)); err == nil {
+		t.Errorf("Render: series shorter than labels; got no error")
+	}
+	if err = (Chart{}).Render(new(bytes.Buffer)); err == nil {
+		t.Errorf("Render: empty chart; got no error")
+	}
+}
+
+func TestNiceMax(t *testing.T) {
+	cases := []struct {
+		max  float64
+		want float64
+	}{
+		{0, 1},
+		{0.3, 0.5},
+		{7, 10},
+		{12, 20},
+		{200, 200},
+		{420, 500},
+	}
+	for _, c := range cases {
+		if got := niceMax(c.max); got != c.want {
+			t.Errorf("niceMax: %v; got %v; want %v", c.max, got, c.want)
+		}
+	}
+}
+
+func TestRenderTable(t *testing.T) {
+	table := Table{
+		Title:  "go vs rust: last 30 days",
+		Header: []string{"tag", "posts"},
+		Rows:   [][]string{{"go", "12"}, {"rust", "7"}},
+	}
+
+	buf := new(bytes.Buffer)
+	if err := table.Render(buf); err != nil {
+		t.Fatalf("Table.Render: got error %v", err)
+	}
+	img, err := png.Decode(buf)
+	if err != nil {
+		t.Fatalf("Table.Render: got invalid PNG %v", err)
+	}
+	if img.Bounds().Dy() != titleHeight+3*rowHeight+tableMargin {
+		t.Errorf("Table.Render: got size %v", img.Bounds())
+	}
+
+	table.Rows = append(table.Rows, []string{"zig"})
+	if err = table.Render(new(bytes.Buffer)); err == nil {
+		t.Errorf("Table.Render: short row; got no error")
+	}
+}
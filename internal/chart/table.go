@@ -0,0 +1,79 @@
+package chart
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+	"image/png"
+	"io"
+)
+
+const (
+	cellPadding = 12
+	rowHeight   = 30
+	tableMargin = 20
+)
+
+// Table is a text table drawn as an image, for tables too wide to be sent as text.
+// The font has ASCII glyphs only, other characters are drawn as '?'.
+type Table struct {
+	Title  string
+	Header []string
+	Rows   [][]string
+}
+
+// Render draws table as PNG image to w. Every row has to have a cell for every header column.
+func (t Table) Render(w io.Writer) error {
+	if len(t.Header) == 0 {
+		return fmt.Errorf("table has no columns")
+	}
+	widths := make([]int, len(t.Header))
+	for i, h := range t.Header {
+		widths[i] = textWidth(h)
+	}
+	for n, row := range t.Rows {
+		if len(row) != len(t.Header) {
+			return fmt.Errorf("row %d has %d cells for %d columns", n, len(row), len(t.Header))
+		}
+		for i, cell := range row {
+			if w := textWidth(cell); w > widths[i] {
+				widths[i] = w
+			}
+		}
+	}
+
+	tableWidth := 0
+	for _, w := range widths {
+		tableWidth += w + 2*cellPadding
+	}
+	imgWidth := tableWidth + 2*tableMargin
+	if tw := textWidth(t.Title) + 2*tableMargin; tw > imgWidth {
+		imgWidth = tw
+	}
+	height := titleHeight + (len(t.Rows)+1)*rowHeight + tableMargin
+	img := image.NewRGBA(image.Rect(0, 0, imgWidth, height))
+	draw.Draw(img, img.Bounds(), &image.Uniform{background}, image.Point{}, draw.Src)
+
+	drawText(img, (imgWidth-textWidth(t.Title))/2, 16, t.Title, foreground)
+
+	y := titleHeight
+	fill(img, image.Rect(tableMargin, y, tableMargin+tableWidth, y+rowHeight), grid)
+	t.drawRow(img, y, widths, t.Header)
+	for _, row := range t.Rows {
+		y += rowHeight
+		fill(img, image.Rect(tableMargin, y, tableMargin+tableWidth, y+1), grid)
+		t.drawRow(img, y, widths, row)
+	}
+	y += rowHeight
+	fill(img, image.Rect(tableMargin, y, tableMargin+tableWidth, y+1), grid)
+
+	return png.Encode(w, img)
+}
+
+func (t Table) drawRow(img *image.RGBA, y int, widths []int, cells []string) {
+	x := tableMargin
+	for i, cell := range cells {
+		drawText(img, x+cellPadding, y+(rowHeight-glyphHeight*fontScale)/2, cell, foreground)
+		x += widths[i] + 2*cellPadding
+	}
+}
@@ -0,0 +1,48 @@
+package debounce
+
+import (
+	"sync"
+	"time"
+)
+
+// Debouncer lets an event with the same key happen at most once per window.
+type Debouncer struct {
+	mu     sync.Mutex
+	window time.Duration
+	last   map[string]time.Time
+}
+
+// New returns Debouncer with window.
+func New(window time.Duration) *Debouncer {
+	return &Debouncer{window: window, last: make(map[string]time.Time)}
+}
+
+// Ready reports whether the event with key may happen at now without remembering it.
+func (d *Debouncer) Ready(key string, now time.Time) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	last, ok := d.last[key]
+	return !ok || now.Sub(last) >= d.window
+}
+
+// Allow reports whether the event with key may happen at now and remembers it if so.
+func (d *Debouncer) Allow(key string, now time.Time) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if last, ok := d.last[key]; ok && now.Sub(last) < d.window {
+		return false
+	}
+	d.last[key] = now
+
+	// drop expired keys from time to time so the map does not grow forever
+	if len(d.last)%1024 == 0 {
+		for k, t := range d.last {
+			if now.Sub(t) >= d.window {
+				delete(d.last, k)
+			}
+		}
+	}
+	return true
+}
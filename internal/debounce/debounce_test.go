@@ -0,0 +1,32 @@
+package debounce
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAllow(t *testing.T) {
+	now := time.Now()
+	d := New(time.Minute)
+
+	cases := []struct {
+		name string
+		key  string
+		at   time.Time
+		want bool
+	}{
+		{"first event", "a", now, true},
+		{"repeated in window", "a", now.Add(30 * time.Second), false},
+		{"other key", "b", now.Add(30 * time.Second), true},
+		{"after window", "a", now.Add(time.Minute), true},
+		{"window restarted", "a", now.Add(90 * time.Second), false},
+	}
+	for _, c := range cases {
+		if got := d.Ready(c.key, c.at); got != c.want {
+			t.Errorf("Ready: %s; got %v; want %v", c.name, got, c.want)
+		}
+		if got := d.Allow(c.key, c.at); got != c.want {
+			t.Errorf("Allow: %s; got %v; want %v", c.name, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,237 @@
+package devto
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrNotFound is returned when API has no requested object.
+	ErrNotFound = errors.New("not found")
+	// ErrUnauthorized is returned when API key is wrong or revoked.
+	ErrUnauthorized = errors.New("unauthorized")
+)
+
+// DefaultClient is the Client used by package level functions.
+var DefaultClient = NewClient()
+
+// Client makes requests to DEV.TO API.
+type Client struct {
+	baseURL string
+	http    *http.Client
+}
+
+type ClientOption func(*Client)
+
+// WithBaseURL sets API root, e.g. a local fake in tests.
+func WithBaseURL(baseURL string) ClientOption {
+	return func(c *Client) {
+		c.baseURL = strings.TrimRight(baseURL, "/")
+	}
+}
+
+// WithHTTPClient sets HTTP client which makes requests.
+func WithHTTPClient(h *http.Client) ClientOption {
+	return func(c *Client) {
+		c.http = h
+	}
+}
+
+// WithTransport sets transport of the default HTTP client, e.g. a cache shared by several clients.
+func WithTransport(rt http.RoundTripper) ClientOption {
+	return func(c *Client) {
+		c.http = &http.Client{Timeout: defaultTimeout, Transport: rt}
+	}
+}
+
+// NewClient makes Client to DEV.TO API.
+func NewClient(opts ...ClientOption) *Client {
+	c := &Client{
+		baseURL: defaultBaseURL,
+		http:    &http.Client{Timeout: defaultTimeout},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// GetArticles makes request to DEV.TO API and return Articles struct
+func (c *Client) GetArticles(tag, fresh string) (*Articles, error) {
+	articles := new(Articles)
+	params := url.Values{"tag": {tag}, "top": {fresh}}
+	if err := c.get("/articles?"+params.Encode(), articles); err != nil {
+		return nil, err
+	}
+	return articles, nil
+}
+
+// GetPage returns page of articles with tag, the most popular go first.
+func (c *Client) GetPage(tag string, page, perPage int) (*Articles, error) {
+	articles := new(Articles)
+	params := url.Values{"tag": {tag}, "page": {strconv.Itoa(page)}, "per_page": {strconv.Itoa(perPage)}}
+	if err := c.get("/articles?"+params.Encode(), articles); err != nil {
+		return nil, err
+	}
+	return articles, nil
+}
+
+// GetLatest returns page of the most recently published articles.
+func (c *Client) GetLatest(page, perPage int) (*Articles, error) {
+	articles := new(Articles)
+	params := url.Values{"page": {strconv.Itoa(page)}, "per_page": {strconv.Itoa(perPage)}}
+	if err := c.get("/articles/latest?"+params.Encode(), articles); err != nil {
+		return nil, err
+	}
+	return articles, nil
+}
+
+// GetArticleByPath returns article by its path, e.g. 'username/article-slug'.
+func (c *Client) GetArticleByPath(path string) (*Article, error) {
+	article := new(Article)
+	if err := c.get("/articles/"+strings.Trim(path, "/"), article); err != nil {
+		return nil, err
+	}
+	return article, nil
+}
+
+// GetArticle returns article by id.
+func (c *Client) GetArticle(id int) (*Article, error) {
+	article := new(Article)
+	if err := c.get("/articles/"+strconv.Itoa(id), article); err != nil {
+		return nil, err
+	}
+	return article, nil
+}
+
+// GetArticleBody returns article by id with its body.
+func (c *Client) GetArticleBody(id int) (*ArticleBody, error) {
+	article := new(ArticleBody)
+	if err := c.get("/articles/"+strconv.Itoa(id), article); err != nil {
+		return nil, err
+	}
+	return article, nil
+}
+
+// GetUser returns profile of user by username.
+func (c *Client) GetUser(username string) (*Profile, error) {
+	profile := new(Profile)
+	params := url.Values{"url": {username}}
+	if err := c.get("/users/by_username?"+params.Encode(), profile); err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
+
+// GetUserArticles returns the latest articles of user.
+func (c *Client) GetUserArticles(username string, perPage int) (*Articles, error) {
+	articles := new(Articles)
+	params := url.Values{"username": {username}, "per_page": {strconv.Itoa(perPage)}}
+	if err := c.get("/articles?"+params.Encode(), articles); err != nil {
+		return nil, err
+	}
+	return articles, nil
+}
+
+// GetMe returns profile of the owner of API key.
+func (c *Client) GetMe(apiKey string) (*Profile, error) {
+	profile := new(Profile)
+	if err := c.getWithKey("/users/me", apiKey, profile); err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
+
+// GetFollowedTags returns tags followed by the owner of API key.
+func (c *Client) GetFollowedTags(apiKey string) ([]FollowedTag, error) {
+	var tags []FollowedTag
+	if err := c.getWithKey("/follows/tags", apiKey, &tags); err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// GetReadingList returns page of the reading list of the owner of API key.
+func (c *Client) GetReadingList(apiKey string, page, perPage int) ([]ReadingListItem, error) {
+	var items []ReadingListItem
+	params := url.Values{"page": {strconv.Itoa(page)}, "per_page": {strconv.Itoa(perPage)}}
+	if err := c.getWithKey("/readinglist?"+params.Encode(), apiKey, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// CreateArticle creates article on behalf of the owner of API key and returns it.
+// The article stays a draft unless it is published.
+func (c *Client) CreateArticle(apiKey string, a NewArticle) (*Article, error) {
+	body, err := json.Marshal(map[string]NewArticle{"article": a})
+	if err != nil {
+		return nil, fmt.Errorf("error when marshal article: %v", err)
+	}
+	req, err := http.NewRequest(http.MethodPost, c.baseURL+"/articles", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("error when makes request: %v", err)
+	}
+	req.Header.Set("api-key", apiKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	article := new(Article)
+	if err = c.do(req, http.StatusCreated, article); err != nil {
+		return nil, err
+	}
+	return article, nil
+}
+
+// get makes GET request to API path and decodes JSON response into v.
+func (c *Client) get(path string, v interface{}) error {
+	return c.getWithKey(path, "", v)
+}
+
+// getWithKey makes GET request authorized with API key unless it is empty.
+func (c *Client) getWithKey(path, apiKey string, v interface{}) error {
+	req, err := http.NewRequest(http.MethodGet, c.baseURL+path, nil)
+	if err != nil {
+		return fmt.Errorf("error when makes request: %v", err)
+	}
+	if apiKey != "" {
+		req.Header.Set("api-key", apiKey)
+	}
+	return c.do(req, http.StatusOK, v)
+}
+
+// do makes request and decodes JSON response into v if response has the expected status.
+func (c *Client) do(req *http.Request, status int, v interface{}) error {
+	url := req.URL.String()
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("error when makes http %s to %s: %v", req.Method, url, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("error when reads from response body: %v", err)
+	}
+	switch resp.StatusCode {
+	case status:
+	case http.StatusNotFound:
+		return fmt.Errorf("error when makes http %s to %s: %w", req.Method, url, ErrNotFound)
+	case http.StatusUnauthorized:
+		return fmt.Errorf("error when makes http %s to %s: %w", req.Method, url, ErrUnauthorized)
+	default:
+		return fmt.Errorf("error when makes http %s to %s: status %s", req.Method, url, resp.Status)
+	}
+
+	if err = json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("error when unmarshal body: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,196 @@
+package devto
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTagsUnmarshal(t *testing.T) {
+	cases := []struct {
+		name string
+		data string
+		want Tags
+	}{
+		{"array", `["go", "webdev"]`, Tags{"go", "webdev"}},
+		{"string", `"go, webdev"`, Tags{"go", "webdev"}},
+		{"empty string", `""`, nil},
+	}
+	for _, c := range cases {
+		var got Tags
+		if err := json.Unmarshal([]byte(c.data), &got); err != nil {
+			t.Errorf("Tags: %s; got error %v", c.name, err)
+		}
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("Tags: %s; got %v; want %v", c.name, got, c.want)
+		}
+	}
+}
+
+func TestGetArticleByPath(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/articles/ben/hello-go-1a2b" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"id": 1, "title": "Hello Go", "tag_list": "go, beginners", "reading_time_minutes": 4, "user": {"username": "ben"}}`))
+	}))
+	defer srv.Close()
+	c := NewClient(WithBaseURL(srv.URL))
+
+	article, err := c.GetArticleByPath("/ben/hello-go-1a2b")
+	if err != nil {
+		t.Fatalf("GetArticleByPath: got error %v", err)
+	}
+	want := &Article{ID: 1, Title: "Hello Go", ReadingTime: 4, Tags: Tags{"go", "beginners"}, User: User{Username: "ben"}}
+	if !reflect.DeepEqual(article, want) {
+		t.Errorf("GetArticleByPath: got %+v; want %+v", article, want)
+	}
+
+	if _, err = c.GetArticleByPath("ben/missing"); err == nil {
+		t.Errorf("GetArticleByPath: missing article; got no error")
+	}
+}
+
+func TestGetArticle(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/articles/1" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"id": 1, "title": "Hello Go", "positive_reactions_count": 40, "comments_count": 3}`))
+	}))
+	defer srv.Close()
+	c := NewClient(WithBaseURL(srv.URL))
+
+	article, err := c.GetArticle(1)
+	if err != nil {
+		t.Fatalf("GetArticle: got error %v", err)
+	}
+	if article.ID != 1 || article.Score != 40 || article.Comments != 3 {
+		t.Errorf("GetArticle: got %+v; want article 1 with 40 reactions and 3 comments", article)
+	}
+	if _, err = c.GetArticle(2); err == nil {
+		t.Errorf("GetArticle: missing article; got no error")
+	}
+}
+
+func TestGetArticleBody(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/articles/1" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"id": 1, "title": "Hello Go", "tag_list": "go", "body_markdown": "# Hello"}`))
+	}))
+	defer srv.Close()
+	c := NewClient(WithBaseURL(srv.URL))
+
+	article, err := c.GetArticleBody(1)
+	if err != nil {
+		t.Fatalf("GetArticleBody: got error %v", err)
+	}
+	want := &ArticleBody{Article: Article{ID: 1, Title: "Hello Go", Tags: Tags{"go"}}, BodyMarkdown: "# Hello"}
+	if !reflect.DeepEqual(article, want) {
+		t.Errorf("GetArticleBody: got %+v; want %+v", article, want)
+	}
+	if _, err = c.GetArticleBody(2); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetArticleBody: missing article; got error %v; want ErrNotFound", err)
+	}
+}
+
+func TestGetUser(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/users/by_username" || r.URL.Query().Get("url") != "ben" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"id": 1, "username": "ben", "name": "Ben Halpern", "joined_at": "Dec 27, 2015"}`))
+	}))
+	defer srv.Close()
+	c := NewClient(WithBaseURL(srv.URL))
+
+	profile, err := c.GetUser("ben")
+	if err != nil {
+		t.Fatalf("GetUser: got error %v", err)
+	}
+	want := &Profile{ID: 1, Username: "ben", Name: "Ben Halpern", JoinedAt: "Dec 27, 2015"}
+	if !reflect.DeepEqual(profile, want) {
+		t.Errorf("GetUser: got %+v; want %+v", profile, want)
+	}
+
+	if _, err = c.GetUser("nobody"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetUser: missing user; got error %v; want %v", err, ErrNotFound)
+	}
+}
+
+func TestGetFollowedTags(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("api-key") != "s3cr3t" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`[{"id": 1, "name": "go", "points": 2.0}, {"id": 2, "name": "rust", "points": 1.0}]`))
+	}))
+	defer srv.Close()
+	c := NewClient(WithBaseURL(srv.URL))
+
+	tags, err := c.GetFollowedTags("s3cr3t")
+	if err != nil {
+		t.Fatalf("GetFollowedTags: got error %v", err)
+	}
+	want := []FollowedTag{{1, "go", 2}, {2, "rust", 1}}
+	if !reflect.DeepEqual(tags, want) {
+		t.Errorf("GetFollowedTags: got %+v; want %+v", tags, want)
+	}
+
+	_, err = c.GetFollowedTags("wrong")
+	if !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("GetFollowedTags: wrong key; got error %v; want %v", err, ErrUnauthorized)
+	}
+	if strings.Contains(err.Error(), "wrong") {
+		t.Errorf("GetFollowedTags: error %q contains the key", err)
+	}
+}
+
+func TestCreateArticle(t *testing.T) {
+	var got map[string]NewArticle
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/articles" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Header.Get("api-key") != "s3cr3t" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id": 7, "title": "Hello", "url": "https://dev.to/ben/hello-temp-slug-1", "path": "/ben/hello-temp-slug-1"}`))
+	}))
+	defer srv.Close()
+	c := NewClient(WithBaseURL(srv.URL))
+
+	draft := NewArticle{Title: "Hello", BodyMarkdown: "# Hi", Tags: []string{"go"}}
+	article, err := c.CreateArticle("s3cr3t", draft)
+	if err != nil {
+		t.Fatalf("CreateArticle: got error %v", err)
+	}
+	if article.ID != 7 || article.Url != "https://dev.to/ben/hello-temp-slug-1" {
+		t.Errorf("CreateArticle: got %+v", article)
+	}
+	if !reflect.DeepEqual(got["article"], draft) {
+		t.Errorf("CreateArticle: sent %+v; want %+v", got["article"], draft)
+	}
+
+	if _, err = c.CreateArticle("wrong", draft); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("CreateArticle: wrong key; got error %v; want %v", err, ErrUnauthorized)
+	}
+}
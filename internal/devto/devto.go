@@ -4,18 +4,19 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
-	"io/ioutil"
-	"net/http"
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 )
 
 const (
 	defaultTag       string = ""
 	defaultFreshness string = "10"
 	defaultLimit     int    = 10
-	url                     = "https://dev.to/api/articles"
+	defaultBaseURL          = "https://dev.to/api"
+	defaultTimeout          = 30 * time.Second
+	growthBadgeMin          = 5
 	dotSymbol               = 9865 // unicode symbol of dot '⚉' https://unicodeplus.com/U+2689
 	rgxp                    = `^/article\s{1}[a-zA-z]+\s[1-9][0-9]*\s[1-9][0-9]*$|^/article\s{1}[a-zA-z]+\s[1-9][0-9]*$|^/article\s{1}[a-zA-z]*$|^/article$`
 )
@@ -27,12 +28,104 @@ type Query struct {
 }
 
 type Article struct {
-	Title string `json:"title"`
-	Url   string `json:"url"`
-	Score int    `json:"positive_reactions_count"`
+	ID          int       `json:"id"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	Url         string    `json:"url"`
+	Path        string    `json:"path"`
+	Score       int       `json:"positive_reactions_count"`
+	Comments    int       `json:"comments_count"`
+	ReadingTime int       `json:"reading_time_minutes"`
+	PublishedAt time.Time `json:"published_at"`
+	Tags        Tags      `json:"tag_list"`
+	User        User      `json:"user"`
+	// Growth is how many reactions the article got during the last day, it is tracked by the bot.
+	Growth int `json:"-"`
 }
 type Articles []Article
 
+type User struct {
+	Name     string `json:"name"`
+	Username string `json:"username"`
+}
+
+// Profile is a public profile of DEV.TO user.
+type Profile struct {
+	ID       int    `json:"id"`
+	Username string `json:"username"`
+	Name     string `json:"name"`
+	Summary  string `json:"summary"`
+	Location string `json:"location"`
+	Twitter  string `json:"twitter_username"`
+	GitHub   string `json:"github_username"`
+	Website  string `json:"website_url"`
+	JoinedAt string `json:"joined_at"`
+}
+
+// NewArticle is an article to create with Client.CreateArticle.
+type NewArticle struct {
+	Title        string   `json:"title"`
+	BodyMarkdown string   `json:"body_markdown"`
+	Published    bool     `json:"published"`
+	Tags         []string `json:"tags,omitempty"`
+}
+
+// ArticleBody is an article with its body in Markdown.
+type ArticleBody struct {
+	Article
+	BodyMarkdown string `json:"body_markdown"`
+}
+
+// FollowedTag is a tag followed by DEV.TO user.
+type FollowedTag struct {
+	ID     int     `json:"id"`
+	Name   string  `json:"name"`
+	Points float64 `json:"points"`
+}
+
+// ReadingListItem is an article saved to reading list of DEV.TO user.
+type ReadingListItem struct {
+	ID        int       `json:"id"`
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+	Article   Article   `json:"article"`
+}
+
+// Tags is a list of article tags. DEV.TO returns them as an array in article lists
+// and as a comma separated string for a single article.
+type Tags []string
+
+// UnmarshalJSON decodes tags from an array or a comma separated string.
+func (t *Tags) UnmarshalJSON(data []byte) error {
+	var list []string
+	if err := json.Unmarshal(data, &list); err == nil {
+		*t = list
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("tags are neither an array nor a string: %v", err)
+	}
+	*t = nil
+	for _, tag := range strings.Split(s, ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			*t = append(*t, tag)
+		}
+	}
+	return nil
+}
+
+// Has reports whether tag is in the list.
+func (t Tags) Has(tag string) bool {
+	for _, v := range t {
+		if v == tag {
+			return true
+		}
+	}
+	return false
+}
+
 type QueryOption func(*Query) error
 
 // WithTag adds tag to Query or set default value.
@@ -117,27 +210,58 @@ func NewQuery(opts ...QueryOption) (*Query, error) {
 	return query, nil
 }
 
-// GetArticles makes request to DEV.TO API and return Articles struct
+// GetArticles makes request to DEV.TO API with DefaultClient and return Articles struct
 func GetArticles(tag, fresh string) (*Articles, error) {
-	articles := new(Articles)
-
-	url := fmt.Sprintf("%s?tag=%s&top=%s", url, tag, fresh)
+	return DefaultClient.GetArticles(tag, fresh)
+}
 
-	resp, err := http.Get(url)
-	if err != nil {
-		return nil, fmt.Errorf("error when makes http GET from %s: %v", url, err)
+// Head returns first limit articles.
+func (articles *Articles) Head(limit int) Articles {
+	if len(*articles) <= limit {
+		return *articles
 	}
+	return (*articles)[:limit]
+}
 
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("error when reads from response body: %v", err)
-	}
+var (
+	// markdownEscaper escapes characters which start an entity of a legacy Markdown message.
+	markdownEscaper = strings.NewReplacer("_", "\\_", "*", "\\*", "`", "\\`", "[", "\\[")
+	// linkTextReplacer replaces brackets in text of a link, which is taken as is up to the closing bracket,
+	// so escaping backslashes would be shown there.
+	linkTextReplacer = strings.NewReplacer("[", "(", "]", ")")
+	linkURLReplacer  = strings.NewReplacer(")", "%29")
+	// codeReplacer replaces backticks in text of a code entity, which ends at the first one and can't escape it.
+	codeReplacer = strings.NewReplacer("`", "'")
+)
 
-	if err = json.Unmarshal(body, articles); err != nil {
-		return nil, fmt.Errorf("error when unmarshal body: %v", err)
-	}
-	return articles, nil
+// EscapeMarkdown escapes text placed outside of entities of a legacy Markdown message.
+func EscapeMarkdown(s string) string {
+	return markdownEscaper.Replace(s)
+}
 
+// EscapeCode makes text safe inside a code entity of a legacy Markdown message.
+func EscapeCode(s string) string {
+	return codeReplacer.Replace(s)
+}
+
+// MarkdownLink makes a legacy Markdown link to url with text title.
+func MarkdownLink(title, url string) string {
+	return "[" + linkTextReplacer.Replace(title) + "](" + linkURLReplacer.Replace(url) + ")"
+}
+
+// WriteCard makes a compact description of the article, summary adds its description.
+func (a *Article) WriteCard(summary bool) string {
+	buf := new(bytes.Buffer)
+	buf.WriteString(MarkdownLink(a.Title, a.Url) + "\n")
+	buf.WriteString(fmt.Sprintf("`  %s (@%s) · %d min read`\n", a.User.Name, a.User.Username, a.ReadingTime))
+	if len(a.Tags) > 0 {
+		buf.WriteString("`  #" + strings.Join(a.Tags, " #") + "`\n")
+	}
+	buf.WriteString(fmt.Sprintf("`  Score: %d · Comments: %d%s`\n", a.Score, a.Comments, a.writeGrowth()))
+	if summary && a.Description != "" {
+		buf.WriteString("\n" + EscapeMarkdown(a.Description) + "\n")
+	}
+	return buf.String()
 }
 
 // WriteArticles makes response to user
@@ -149,8 +273,16 @@ func (articles *Articles) WriteArticles(limit int) string {
 			break
 		}
 		buf.WriteRune(dotSymbol)
-		buf.WriteString(fmt.Sprintf(" [%s](%s)\n`  Score: %d`\n\n", a.Title, a.Url, a.Score))
+		buf.WriteString(fmt.Sprintf(" %s\n`  Score: %d%s`\n\n", MarkdownLink(a.Title, a.Url), a.Score, a.writeGrowth()))
 
 	}
 	return buf.String()
 }
+
+// writeGrowth makes a badge of reactions the article got today if they are many enough to notice.
+func (a *Article) writeGrowth() string {
+	if a.Growth < growthBadgeMin {
+		return ""
+	}
+	return fmt.Sprintf(" · 📈 +%d today", a.Growth)
+}
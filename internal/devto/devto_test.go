@@ -80,3 +80,39 @@ func TestWithTag(t *testing.T) {
 		}
 	}
 }
+
+func TestWriteGrowth(t *testing.T) {
+	cases := []struct {
+		growth int
+		want   string
+	}{
+		{0, ""},
+		{growthBadgeMin - 1, ""},
+		{40, " · 📈 +40 today"},
+	}
+	for _, c := range cases {
+		a := Article{Growth: c.growth}
+		if got := a.writeGrowth(); got != c.want {
+			t.Errorf("writeGrowth: growth %d; got %q; want %q", c.growth, got, c.want)
+		}
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	cases := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"escaped text", EscapeMarkdown("snake_case *ptr `x` [1]"), "snake\\_case \\*ptr \\`x\\` \\[1]"},
+		{"plain link", MarkdownLink("Go_1.18 *generics*", "https://dev.to/a_b/go"), "[Go_1.18 *generics*](https://dev.to/a_b/go)"},
+		{"code", EscapeCode("`go` *ptr"), "'go' *ptr"},
+		{"brackets in title", MarkdownLink("[WIP] Rust", "https://dev.to/rust"), "[(WIP) Rust](https://dev.to/rust)"},
+		{"parenthesis in url", MarkdownLink("Go", "https://dev.to/go_(lang)"), "[Go](https://dev.to/go_(lang%29)"},
+	}
+	for _, c := range cases {
+		if c.got != c.want {
+			t.Errorf("Markdown: %s; got %q; want %q", c.name, c.got, c.want)
+		}
+	}
+}
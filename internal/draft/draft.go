@@ -0,0 +1,103 @@
+package draft
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alebsys/telegram-article-bot/internal/store"
+)
+
+const bucket = "drafts"
+
+// Step is what the bot waits for from the writer of a draft.
+type Step string
+
+const (
+	StepTitle Step = "title"
+	StepTags  Step = "tags"
+	StepBody  Step = "body"
+)
+
+// Draft is an article being written in a conversation with the bot.
+type Draft struct {
+	Step    Step      `json:"step"`
+	Title   string    `json:"title,omitempty"`
+	Tags    []string  `json:"tags,omitempty"`
+	Parts   []string  `json:"parts,omitempty"`
+	Started time.Time `json:"started"`
+}
+
+// Markdown returns body of the draft, parts are separated by an empty line.
+func (d Draft) Markdown() string {
+	return strings.Join(d.Parts, "\n\n")
+}
+
+// Drafts keeps drafts of users, one per user.
+type Drafts struct {
+	store store.Store
+}
+
+// New returns Drafts backed by s.
+func New(s store.Store) *Drafts {
+	return &Drafts{store: s}
+}
+
+// Get returns draft of user.
+func (d *Drafts) Get(userID int64) (Draft, bool, error) {
+	var dr Draft
+	ok, err := d.store.Get(bucket, key(userID), &dr)
+	if err != nil {
+		return dr, false, fmt.Errorf("error when reads draft of %d: %v", userID, err)
+	}
+	return dr, ok, nil
+}
+
+// Put saves draft of user replacing the previous one.
+func (d *Drafts) Put(userID int64, dr Draft) error {
+	return d.store.Put(bucket, key(userID), dr)
+}
+
+// Delete removes draft of user.
+func (d *Drafts) Delete(userID int64) error {
+	return d.store.Delete(bucket, key(userID))
+}
+
+// Purge removes drafts started before t.
+func (d *Drafts) Purge(before time.Time) error {
+	keys, err := d.store.Keys(bucket)
+	if err != nil {
+		return err
+	}
+	for _, k := range keys {
+		var dr Draft
+		if _, err := d.store.Get(bucket, k, &dr); err != nil {
+			return err
+		}
+		if dr.Started.Before(before) {
+			if err = d.store.Delete(bucket, k); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Export returns draft of user.
+func (d *Drafts) Export(userID int64) (interface{}, error) {
+	dr, ok, err := d.Get(userID)
+	if err != nil || !ok {
+		return nil, err
+	}
+	return dr, nil
+}
+
+// Forget removes draft of user.
+func (d *Drafts) Forget(userID int64) error {
+	return d.Delete(userID)
+}
+
+func key(userID int64) string {
+	return strconv.FormatInt(userID, 10)
+}
@@ -0,0 +1,34 @@
+package draft
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alebsys/telegram-article-bot/internal/store"
+)
+
+func TestDrafts(t *testing.T) {
+	d := New(store.NewMemory())
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	d.Put(1, Draft{Step: StepBody, Title: "Hello", Parts: []string{"# Intro", "Some text"}, Started: now})
+	d.Put(2, Draft{Step: StepTitle, Started: now.Add(-48 * time.Hour)})
+
+	dr, ok, err := d.Get(1)
+	if err != nil || !ok {
+		t.Fatalf("Get: got %v, %v; want draft", ok, err)
+	}
+	if got, want := dr.Markdown(), "# Intro\n\nSome text"; got != want {
+		t.Errorf("Markdown: got %q; want %q", got, want)
+	}
+
+	if err = d.Purge(now.Add(-24 * time.Hour)); err != nil {
+		t.Fatalf("Purge: got error %v", err)
+	}
+	if _, ok, _ = d.Get(2); ok {
+		t.Errorf("Purge: old draft is kept")
+	}
+	if _, ok, _ = d.Get(1); !ok {
+		t.Errorf("Purge: new draft is removed")
+	}
+}
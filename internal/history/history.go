@@ -0,0 +1,197 @@
+package history
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/alebsys/telegram-article-bot/internal/store"
+)
+
+const (
+	bucket = "history"
+	// lastIDBucket keeps the last entry id of every user, so ids of dropped entries are never reused
+	lastIDBucket = "history_last_id"
+)
+
+// Entry is a single query made by user.
+type Entry struct {
+	// ID identifies the entry among queries of user, it is set by Add.
+	ID      int       `json:"id"`
+	Query   string    `json:"query"`
+	Tag     string    `json:"tag,omitempty"`
+	Results int       `json:"results"`
+	Time    time.Time `json:"time"`
+}
+
+// TagCount is the number of queries with a tag.
+type TagCount struct {
+	Tag   string
+	Count int
+}
+
+// History keeps queries of every user.
+type History struct {
+	mu    sync.Mutex
+	store store.Store
+	limit int
+}
+
+// New returns History backed by s which keeps last limit queries of every user.
+// Zero limit keeps all queries.
+func New(s store.Store, limit int) *History {
+	return &History{store: s, limit: limit}
+}
+
+// Add appends entry to user history with the next id and drops the oldest queries over the limit.
+func (h *History) Add(userID int64, e Entry) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	entries, err := h.list(userID)
+	if err != nil {
+		return err
+	}
+	var last int
+	if _, err = h.store.Get(lastIDBucket, key(userID), &last); err != nil {
+		return err
+	}
+	for _, old := range entries {
+		if old.ID > last {
+			last = old.ID
+		}
+	}
+	e.ID = last + 1
+	entries = append(entries, e)
+	if h.limit > 0 && len(entries) > h.limit {
+		entries = entries[len(entries)-h.limit:]
+	}
+	b := &store.Batch{}
+	b.Put(bucket, key(userID), entries)
+	b.Put(lastIDBucket, key(userID), e.ID)
+	return store.Apply(h.store, b)
+}
+
+// Last returns the newest query of user.
+func (h *History) Last(userID int64) (Entry, bool, error) {
+	entries, err := h.List(userID)
+	if err != nil || len(entries) == 0 {
+		return Entry{}, false, err
+	}
+	return entries[len(entries)-1], true, nil
+}
+
+// Find returns query of user by id.
+func (h *History) Find(userID int64, id int) (Entry, bool, error) {
+	entries, err := h.List(userID)
+	if err != nil {
+		return Entry{}, false, err
+	}
+	for _, e := range entries {
+		if e.ID == id {
+			return e, true, nil
+		}
+	}
+	return Entry{}, false, nil
+}
+
+// List returns user history from the oldest query to the newest one.
+func (h *History) List(userID int64) ([]Entry, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.list(userID)
+}
+
+// Purge removes queries made before t from history of every user.
+func (h *History) Purge(before time.Time) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	keys, err := h.store.Keys(bucket)
+	if err != nil {
+		return err
+	}
+	for _, k := range keys {
+		var entries []Entry
+		if _, err := h.store.Get(bucket, k, &entries); err != nil {
+			return err
+		}
+
+		fresh := entries[:0]
+		for _, e := range entries {
+			if !e.Time.Before(before) {
+				fresh = append(fresh, e)
+			}
+		}
+		if len(fresh) == len(entries) {
+			continue
+		}
+
+		if len(fresh) == 0 {
+			err = h.store.Delete(bucket, k)
+		} else {
+			err = h.store.Put(bucket, k, fresh)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Export returns everything stored about user.
+func (h *History) Export(userID int64) (interface{}, error) {
+	return h.List(userID)
+}
+
+// Forget removes user history.
+func (h *History) Forget(userID int64) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if err := h.store.Delete(lastIDBucket, key(userID)); err != nil {
+		return err
+	}
+	return h.store.Delete(bucket, key(userID))
+}
+
+func (h *History) list(userID int64) ([]Entry, error) {
+	var entries []Entry
+	if _, err := h.store.Get(bucket, key(userID), &entries); err != nil {
+		return nil, fmt.Errorf("error when reads history of %d: %v", userID, err)
+	}
+	return entries, nil
+}
+
+// TopTags counts tags of entries and returns n most queried ones.
+func TopTags(entries []Entry, n int) []TagCount {
+	counts := make(map[string]int)
+	for _, e := range entries {
+		if e.Tag != "" {
+			counts[e.Tag]++
+		}
+	}
+
+	tags := make([]TagCount, 0, len(counts))
+	for tag, count := range counts {
+		tags = append(tags, TagCount{Tag: tag, Count: count})
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		if tags[i].Count != tags[j].Count {
+			return tags[i].Count > tags[j].Count
+		}
+		return tags[i].Tag < tags[j].Tag
+	})
+
+	if len(tags) > n {
+		tags = tags[:n]
+	}
+	return tags
+}
+
+func key(userID int64) string {
+	return strconv.FormatInt(userID, 10)
+}
@@ -0,0 +1,136 @@
+package history
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/alebsys/telegram-article-bot/internal/store"
+)
+
+func TestPurge(t *testing.T) {
+	now := time.Now()
+	h := New(store.NewMemory(), 0)
+	h.Add(1, Entry{Query: "/article go", Time: now.Add(-48 * time.Hour)})
+	h.Add(1, Entry{Query: "/article rust", Time: now})
+	h.Add(2, Entry{Query: "/article", Time: now.Add(-72 * time.Hour)})
+
+	if err := h.Purge(now.Add(-24 * time.Hour)); err != nil {
+		t.Fatalf("Purge: got error %v", err)
+	}
+
+	cases := []struct {
+		name   string
+		userID int64
+		want   []string
+	}{
+		{"old query removed", 1, []string{"/article rust"}},
+		{"all queries removed", 2, nil},
+		{"unknown user", 3, nil},
+	}
+	for _, c := range cases {
+		entries, _ := h.List(c.userID)
+		if len(entries) != len(c.want) {
+			t.Errorf("Purge: %s; got %v; want %v", c.name, entries, c.want)
+			continue
+		}
+		for i, e := range entries {
+			if e.Query != c.want[i] {
+				t.Errorf("Purge: %s; got %v; want %v", c.name, e.Query, c.want[i])
+			}
+		}
+	}
+}
+
+func TestForget(t *testing.T) {
+	h := New(store.NewMemory(), 0)
+	h.Add(1, Entry{Query: "/article go", Time: time.Now()})
+
+	if err := h.Forget(1); err != nil {
+		t.Fatalf("Forget: got error %v", err)
+	}
+	entries, _ := h.List(1)
+	if len(entries) != 0 {
+		t.Errorf("Forget: got %v; want empty history", entries)
+	}
+	h.Add(1, Entry{Query: "/article rust", Time: time.Now()})
+	if e, _, _ := h.Last(1); e.ID != 1 {
+		t.Errorf("Forget: got id %d after forget; want 1", e.ID)
+	}
+}
+
+func TestFind(t *testing.T) {
+	now := time.Now()
+	h := New(store.NewMemory(), 2)
+	for _, q := range []string{"/article go", "/article rust", "/article zig"} {
+		h.Add(1, Entry{Query: q, Time: now})
+	}
+	h.Add(2, Entry{Query: "/article", Time: now})
+
+	cases := []struct {
+		name   string
+		userID int64
+		id     int
+		want   string
+	}{
+		{"same second", 1, 2, "/article rust"},
+		{"newest", 1, 3, "/article zig"},
+		{"dropped over limit", 1, 1, ""},
+		{"other user", 2, 1, "/article"},
+		{"unknown id", 1, 4, ""},
+		{"zero id", 1, 0, ""},
+	}
+	for _, c := range cases {
+		e, ok, err := h.Find(c.userID, c.id)
+		if err != nil {
+			t.Fatalf("Find: %s; got error %v", c.name, err)
+		}
+		if ok != (c.want != "") || e.Query != c.want {
+			t.Errorf("Find: %s; got %q, %v; want %q", c.name, e.Query, ok, c.want)
+		}
+	}
+
+	h.Purge(now.Add(time.Hour))
+	h.Add(1, Entry{Query: "/article go", Time: now})
+	if e, _, _ := h.Last(1); e.ID != 4 {
+		t.Errorf("Add: after purge got id %d; want 4", e.ID)
+	}
+}
+
+func TestAddLimit(t *testing.T) {
+	h := New(store.NewMemory(), 2)
+	for _, q := range []string{"/article go", "/article rust", "/article zig"} {
+		h.Add(1, Entry{Query: q, Time: time.Now()})
+	}
+
+	entries, _ := h.List(1)
+	want := []string{"/article rust", "/article zig"}
+	if len(entries) != len(want) {
+		t.Fatalf("Add: got %v; want %v", entries, want)
+	}
+	for i, e := range entries {
+		if e.Query != want[i] {
+			t.Errorf("Add: got %v; want %v", e.Query, want[i])
+		}
+	}
+}
+
+func TestTopTags(t *testing.T) {
+	entries := []Entry{{Tag: "go"}, {Tag: "rust"}, {Tag: "go"}, {}, {Tag: "zig"}}
+
+	cases := []struct {
+		name string
+		n    int
+		want []TagCount
+	}{
+		{"top one", 1, []TagCount{{"go", 2}}},
+		{"ties sorted by name", 3, []TagCount{{"go", 2}, {"rust", 1}, {"zig", 1}}},
+		{"more than tags", 10, []TagCount{{"go", 2}, {"rust", 1}, {"zig", 1}}},
+	}
+	for _, c := range cases {
+		got := TopTags(entries, c.n)
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("TopTags: %s; got %v; want %v", c.name, got, c.want)
+		}
+	}
+}
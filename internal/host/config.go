@@ -0,0 +1,177 @@
+package host
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+
+	"github.com/alebsys/telegram-article-bot/internal/proxy"
+	"github.com/alebsys/telegram-article-bot/internal/secret"
+)
+
+const (
+	defaultCacheSeconds = 300
+	defaultDevtoRate    = 3
+	defaultDevtoBurst   = 10
+)
+
+var namespaceRgxp = regexp.MustCompile(`^[a-z0-9_-]*$`)
+
+// Config describes bots hosted by one process.
+type Config struct {
+	Bots []BotConfig `json:"bots"`
+	// CacheSeconds is how long responses of sources are cached for all bots.
+	CacheSeconds int `json:"cache_seconds"`
+	// DevtoRate is how many requests per second all bots together make to a DEV.TO host.
+	DevtoRate  float64 `json:"devto_rate"`
+	DevtoBurst int     `json:"devto_burst"`
+	// Proxies are outbound proxies of all bots.
+	Proxies Proxies `json:"proxies"`
+}
+
+// Proxies are outbound proxies for Telegram API and every source, empty ones use proxy from the environment.
+type Proxies struct {
+	Telegram proxy.Config `json:"telegram"`
+	Devto    proxy.Config `json:"devto"`
+	// Web is the proxy for pages of links which are not on DEV.TO.
+	Web proxy.Config `json:"web"`
+}
+
+// override returns p with proxies set in o.
+func (p Proxies) override(o Proxies) Proxies {
+	if o.Telegram.URL != "" {
+		p.Telegram = o.Telegram
+	}
+	if o.Devto.URL != "" {
+		p.Devto = o.Devto
+	}
+	if o.Web.URL != "" {
+		p.Web = o.Web
+	}
+	return p
+}
+
+func (p Proxies) check() error {
+	for name, c := range map[string]proxy.Config{"telegram": p.Telegram, "devto": p.Devto, "web": p.Web} {
+		if err := c.Check(); err != nil {
+			return fmt.Errorf("%s proxy: %v", name, err)
+		}
+	}
+	return nil
+}
+
+// BotConfig describes one bot.
+type BotConfig struct {
+	Name string `json:"name"`
+	// TokenEnv is the environment variable with Telegram token of the bot, so tokens don't get into the config file.
+	TokenEnv string `json:"token_env"`
+	Token    string `json:"token"`
+	// Namespace prefixes buckets of the bot state: settings, subscriptions, history and so on.
+	// Every bot of several needs its own namespace.
+	Namespace  string  `json:"namespace"`
+	AllowUsers []int64 `json:"allow_users"`
+	AllowChats []int64 `json:"allow_chats"`
+	// DevtoURL is the root of DEV.TO API the bot reads articles from, e.g. a fake for staging.
+	DevtoURL             string `json:"devto_url"`
+	HistoryLimit         int    `json:"history_limit"`
+	HistoryRetentionDays *int   `json:"history_retention_days"`
+	// Proxies override proxies of the config for the bot.
+	Proxies Proxies `json:"proxies"`
+}
+
+// ReadConfig reads Config from JSON and checks it.
+func ReadConfig(r io.Reader) (*Config, error) {
+	c := &Config{}
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(c); err != nil {
+		return nil, fmt.Errorf("error when decodes config: %v", err)
+	}
+	c.setDefaults()
+	return c, c.check()
+}
+
+// NewConfig returns Config of bots with default shared settings.
+func NewConfig(bots ...BotConfig) (*Config, error) {
+	c := &Config{Bots: bots}
+	c.setDefaults()
+	return c, c.check()
+}
+
+func (c *Config) setDefaults() {
+	if c.CacheSeconds == 0 {
+		c.CacheSeconds = defaultCacheSeconds
+	}
+	if c.DevtoRate == 0 {
+		c.DevtoRate = defaultDevtoRate
+	}
+	if c.DevtoBurst == 0 {
+		c.DevtoBurst = defaultDevtoBurst
+	}
+}
+
+// LoadConfig reads Config from file at path.
+func LoadConfig(path string) (*Config, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadConfig(f)
+}
+
+func (c *Config) check() error {
+	if len(c.Bots) == 0 {
+		return fmt.Errorf("no bots in config")
+	}
+	if err := c.Proxies.check(); err != nil {
+		return err
+	}
+	names := make(map[string]bool)
+	namespaces := make(map[string]string)
+	for i, b := range c.Bots {
+		if b.Name == "" {
+			return fmt.Errorf("bot %d has no name", i)
+		}
+		if names[b.Name] {
+			return fmt.Errorf("duplicate bot %s", b.Name)
+		}
+		names[b.Name] = true
+		if (b.Token == "") == (b.TokenEnv == "") {
+			return fmt.Errorf("bot %s needs either token or token_env", b.Name)
+		}
+		if !namespaceRgxp.MatchString(b.Namespace) {
+			return fmt.Errorf("bot %s: namespace may have only a-z, 0-9, '_' and '-'", b.Name)
+		}
+		if b.Namespace == "" && len(c.Bots) > 1 {
+			return fmt.Errorf("bot %s has no namespace", b.Name)
+		}
+		if other, ok := namespaces[b.Namespace]; ok {
+			return fmt.Errorf("bots %s and %s have the same namespace %q", other, b.Name, b.Namespace)
+		}
+		namespaces[b.Namespace] = b.Name
+		if err := b.Proxies.check(); err != nil {
+			return fmt.Errorf("bot %s: %v", b.Name, err)
+		}
+	}
+	return nil
+}
+
+// ProxiesOf returns proxies of bot.
+func (c *Config) ProxiesOf(b BotConfig) Proxies {
+	return c.Proxies.override(b.Proxies)
+}
+
+// TokenValue returns Telegram token of the bot.
+func (b BotConfig) TokenValue() (secret.Value, error) {
+	if b.TokenEnv == "" {
+		return secret.NewValue(b.Token), nil
+	}
+	token := os.Getenv(b.TokenEnv)
+	if token == "" {
+		return secret.Value{}, fmt.Errorf("bot %s: %s is empty", b.Name, b.TokenEnv)
+	}
+	return secret.NewValue(token), nil
+}
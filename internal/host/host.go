@@ -0,0 +1,93 @@
+package host
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	minBackoff = time.Second
+	maxBackoff = 5 * time.Minute
+	// healthyRun is how long a bot has to run to reset its backoff.
+	healthyRun = 10 * time.Minute
+)
+
+// Supervise calls run until it returns nil. A failing or panicking run is restarted
+// with growing delays, so one broken bot doesn't take down the others.
+func Supervise(name string, run func() error) {
+	supervise(name, run, time.Sleep)
+}
+
+func supervise(name string, run func() error, sleep func(time.Duration)) {
+	backoff := minBackoff
+	for {
+		started := time.Now()
+		err := protect(run)
+		if err == nil {
+			log.Printf("bot %s stopped", name)
+			return
+		}
+		if time.Since(started) > healthyRun {
+			backoff = minBackoff
+		}
+		log.Printf("bot %s failed: %v; restarting in %v", name, err, backoff)
+		sleep(backoff)
+		if backoff *= 2; backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
+
+// protect calls fn and turns its panic into error.
+func protect(fn func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return fn()
+}
+
+// Redactor is a logger which replaces secrets, e.g. bot tokens in URLs of Telegram API, with ***.
+type Redactor struct {
+	mu      sync.RWMutex
+	secrets []string
+}
+
+// Add adds secret to redact unless it is already added, e.g. by a previous run of a restarted bot.
+func (r *Redactor) Add(secret string) {
+	if secret == "" {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, s := range r.secrets {
+		if s == secret {
+			return
+		}
+	}
+	r.secrets = append(r.secrets, secret)
+}
+
+// Redact returns s without secrets.
+func (r *Redactor) Redact(s string) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, secret := range r.secrets {
+		s = strings.Replace(s, secret, "***", -1)
+	}
+	return s
+}
+
+// Println logs like log.Println.
+func (r *Redactor) Println(v ...interface{}) {
+	log.Print(r.Redact(fmt.Sprintln(v...)))
+}
+
+// Printf logs like log.Printf.
+func (r *Redactor) Printf(format string, v ...interface{}) {
+	log.Print(r.Redact(fmt.Sprintf(format, v...)))
+}
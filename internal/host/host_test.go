@@ -0,0 +1,88 @@
+package host
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadConfig(t *testing.T) {
+	cases := []struct {
+		name   string
+		config string
+		failed bool
+	}{
+		{"bots", `{"bots": [{"name": "team", "token_env": "TEAM_TOKEN", "namespace": "team", "allow_chats": [-100]},
+			{"name": "public", "token_env": "PUBLIC_TOKEN", "namespace": "public"}]}`, false},
+		{"one bot without namespace", `{"bots": [{"name": "team", "token": "1"}]}`, false},
+		{"no namespace", `{"bots": [{"name": "team", "token": "1", "namespace": "team"}, {"name": "public", "token": "2"}]}`, true},
+		{"duplicate namespace", `{"bots": [{"name": "team", "token": "1", "namespace": "a"}, {"name": "public", "token": "2", "namespace": "a"}]}`, true},
+		{"no bots", `{"bots": []}`, true},
+		{"duplicate", `{"bots": [{"name": "team", "token": "1"}, {"name": "team", "token": "2"}]}`, true},
+		{"no token", `{"bots": [{"name": "team"}]}`, true},
+		{"bad namespace", `{"bots": [{"name": "team", "token": "1", "namespace": "a/b"}]}`, true},
+		{"bad proxy", `{"bots": [{"name": "team", "token": "1", "proxies": {"devto": {"url": "ftp://proxy"}}}]}`, true},
+		{"unknown field", `{"bots": [{"name": "team", "token": "1", "tokn": "2"}]}`, true},
+	}
+	for _, c := range cases {
+		cfg, err := ReadConfig(strings.NewReader(c.config))
+		if (err != nil) != c.failed {
+			t.Errorf("ReadConfig: %s; got error %v; want error %v", c.name, err, c.failed)
+			continue
+		}
+		if err == nil && (cfg.CacheSeconds != defaultCacheSeconds || cfg.DevtoRate != defaultDevtoRate) {
+			t.Errorf("ReadConfig: %s; got no defaults %+v", c.name, cfg)
+		}
+	}
+}
+
+func TestProxiesOf(t *testing.T) {
+	cfg, err := ReadConfig(strings.NewReader(`{
+		"bots": [{"name": "team", "token": "1", "proxies": {"devto": {"url": "socks5://team-proxy:1080"}}}],
+		"proxies": {"telegram": {"url": "http://proxy:3128", "no_proxy": ["localhost"]}, "devto": {"url": "http://proxy:3128"}}
+	}`))
+	if err != nil {
+		t.Fatalf("ReadConfig: got error %v", err)
+	}
+	p := cfg.ProxiesOf(cfg.Bots[0])
+	if p.Telegram.URL != "http://proxy:3128" || p.Devto.URL != "socks5://team-proxy:1080" || p.Web.URL != "" {
+		t.Errorf("ProxiesOf: got %+v", p)
+	}
+}
+
+func TestSupervise(t *testing.T) {
+	runs := 0
+	var delays []time.Duration
+	supervise("test", func() error {
+		runs++
+		switch runs {
+		case 1:
+			panic("broken")
+		case 2:
+			return errors.New("no network")
+		}
+		return nil
+	}, func(d time.Duration) { delays = append(delays, d) })
+
+	if runs != 3 {
+		t.Errorf("supervise: got %d runs; want 3", runs)
+	}
+	if len(delays) != 2 || delays[1] != 2*delays[0] {
+		t.Errorf("supervise: got delays %v; want growing delays", delays)
+	}
+}
+
+func TestRedactor(t *testing.T) {
+	r := &Redactor{}
+	r.Add("123:ABC")
+	got := r.Redact(`Post "https://api.telegram.org/bot123:ABC/getMe": dial tcp: timeout`)
+	if strings.Contains(got, "123:ABC") {
+		t.Errorf("Redact: got token in %q", got)
+	}
+
+	r.Add("123:ABC")
+	if len(r.secrets) != 1 {
+		t.Errorf("Add: got %d secrets after adding the same one twice; want 1", len(r.secrets))
+	}
+}
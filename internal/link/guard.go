@@ -0,0 +1,105 @@
+package link
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+const maxRedirects = 10
+
+// sharedAddress is carrier-grade NAT range 100.64.0.0/10 which is not reachable from the internet either.
+var sharedAddress = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// Public reports whether ip may be fetched on behalf of users,
+// i.e. it is not a loopback, private, link-local, multicast or unspecified address.
+func Public(ip net.IP) bool {
+	return !(ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() || sharedAddress.Contains(ip))
+}
+
+// checkURL fails unless u is an http(s) URL whose host resolves to public addresses only.
+func checkURL(ctx context.Context, u *url.URL, public func(net.IP) bool) error {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("scheme %q is not allowed", u.Scheme)
+	}
+	_, err := lookup(ctx, u.Hostname(), public)
+	return err
+}
+
+// lookup resolves host and fails if any of its addresses is not public.
+func lookup(ctx context.Context, host string, public func(net.IP) bool) ([]net.IP, error) {
+	var ips []net.IP
+	if ip := net.ParseIP(host); ip != nil {
+		ips = []net.IP{ip}
+	} else {
+		addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+		if err != nil {
+			return nil, fmt.Errorf("error when resolves %s: %v", host, err)
+		}
+		for _, a := range addrs {
+			ips = append(ips, a.IP)
+		}
+	}
+	if len(ips) == 0 {
+		return nil, fmt.Errorf("host %s has no addresses", host)
+	}
+	for _, ip := range ips {
+		if !public(ip) {
+			return nil, fmt.Errorf("host %s resolves to non-public address %s", host, ip)
+		}
+	}
+	return ips, nil
+}
+
+// guard makes t dial only public addresses. The address is checked after DNS resolution and the checked IP is dialed,
+// so a host can't be rebound to an internal address between the checks. Proxies chosen by t.Proxy are dialed as is.
+func guard(t *http.Transport, public func(net.IP) bool) {
+	var proxies sync.Map
+	if proxy := t.Proxy; proxy != nil {
+		t.Proxy = func(req *http.Request) (*url.URL, error) {
+			u, err := proxy(req)
+			if u != nil {
+				proxies.Store(proxyAddr(u), true)
+			}
+			return u, err
+		}
+	}
+	dial := t.DialContext
+	if dial == nil {
+		dial = (&net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}).DialContext
+	}
+	t.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if _, ok := proxies.Load(addr); ok {
+			return dial(ctx, network, addr)
+		}
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, err
+		}
+		ips, err := lookup(ctx, host, public)
+		if err != nil {
+			return nil, err
+		}
+		return dial(ctx, network, net.JoinHostPort(ips[0].String(), port))
+	}
+}
+
+// proxyAddr returns 'host:port' which http.Transport dials to reach proxy u.
+func proxyAddr(u *url.URL) string {
+	if port := u.Port(); port != "" {
+		return net.JoinHostPort(u.Hostname(), port)
+	}
+	port := "80"
+	switch u.Scheme {
+	case "https":
+		port = "443"
+	case "socks5":
+		port = "1080"
+	}
+	return net.JoinHostPort(u.Hostname(), port)
+}
@@ -0,0 +1,184 @@
+package link
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/alebsys/telegram-article-bot/internal/devto"
+)
+
+const (
+	SourceDevto = "devto"
+	SourceWeb   = "web"
+
+	defaultMaxBytes = 512 << 10
+	defaultTimeout  = 10 * time.Second
+)
+
+var (
+	urlRgxp       = regexp.MustCompile(`https?://[^\s<>"'` + "`" + `]+`)
+	devtoPathRgxp = regexp.MustCompile(`^/([A-Za-z0-9_]+/[A-Za-z0-9_\-]+)/?$`)
+)
+
+// Link is a web page resolved to its metadata.
+type Link struct {
+	URL         string    `json:"url"`
+	Title       string    `json:"title"`
+	Description string    `json:"description,omitempty"`
+	Site        string    `json:"site,omitempty"`
+	Author      string    `json:"author,omitempty"`
+	Tags        []string  `json:"tags,omitempty"`
+	ReadingTime int       `json:"reading_time,omitempty"`
+	ArticleID   int       `json:"article_id,omitempty"`
+	Source      string    `json:"source"`
+	Resolved    time.Time `json:"resolved"`
+}
+
+// ID returns a short stable identifier of the link URL.
+func (l Link) ID() string {
+	sum := sha1.Sum([]byte(l.URL))
+	return hex.EncodeToString(sum[:6])
+}
+
+// Resolver finds metadata of links using DEV.TO API for DEV.TO articles
+// and OpenGraph tags for other pages.
+type Resolver struct {
+	devto    *devto.Client
+	http     *http.Client
+	maxBytes int64
+	public   func(net.IP) bool
+}
+
+// NewResolver returns Resolver which fetches at most maxBytes of a page with a copy of h.
+// Zero maxBytes or nil h use defaults. Pages, redirects and connections are allowed to public addresses only,
+// see Public.
+func NewResolver(d *devto.Client, h *http.Client, maxBytes int64) *Resolver {
+	if maxBytes <= 0 {
+		maxBytes = defaultMaxBytes
+	}
+	r := &Resolver{devto: d, maxBytes: maxBytes, public: Public}
+
+	client := http.Client{Timeout: defaultTimeout}
+	if h != nil {
+		client = *h
+	}
+	if client.Timeout == 0 {
+		client.Timeout = defaultTimeout
+	}
+	if client.Transport == nil {
+		client.Transport = http.DefaultTransport
+	}
+	if t, ok := client.Transport.(*http.Transport); ok {
+		t = t.Clone()
+		guard(t, func(ip net.IP) bool { return r.public(ip) })
+		client.Transport = t
+	}
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if len(via) >= maxRedirects {
+			return fmt.Errorf("stopped after %d redirects", maxRedirects)
+		}
+		return checkURL(req.Context(), req.URL, r.public)
+	}
+	r.http = &client
+	return r
+}
+
+// Resolve returns metadata of the page at rawURL.
+func (r *Resolver) Resolve(rawURL string) (Link, error) {
+	if path, ok := DevtoPath(rawURL); ok {
+		article, err := r.devto.GetArticleByPath(path)
+		if err == nil {
+			return FromArticle(*article), nil
+		}
+	}
+	return r.resolveWeb(rawURL)
+}
+
+// FromArticle makes Link of DEV.TO article.
+func FromArticle(a devto.Article) Link {
+	return Link{
+		URL:         a.Url,
+		Title:       a.Title,
+		Description: a.Description,
+		Site:        "DEV Community",
+		Author:      a.User.Name,
+		Tags:        a.Tags,
+		ReadingTime: a.ReadingTime,
+		ArticleID:   a.ID,
+		Source:      SourceDevto,
+		Resolved:    time.Now(),
+	}
+}
+
+func (r *Resolver) resolveWeb(rawURL string) (Link, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return Link{}, fmt.Errorf("error when parses URL %s: %v", rawURL, err)
+	}
+	if err := checkURL(context.Background(), u, r.public); err != nil {
+		return Link{}, fmt.Errorf("error when checks URL %s: %v", rawURL, err)
+	}
+	resp, err := r.http.Get(rawURL)
+	if err != nil {
+		return Link{}, fmt.Errorf("error when makes http GET from %s: %v", rawURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Link{}, fmt.Errorf("error when makes http GET from %s: status %s", rawURL, resp.Status)
+	}
+	l := Link{URL: rawURL, Source: SourceWeb, Resolved: time.Now()}
+	if ct := resp.Header.Get("Content-Type"); ct != "" && !strings.Contains(ct, "html") {
+		l.Title = rawURL
+		return l, nil
+	}
+
+	body, err := ioutil.ReadAll(&io.LimitedReader{R: resp.Body, N: r.maxBytes})
+	if err != nil {
+		return Link{}, fmt.Errorf("error when reads from response body: %v", err)
+	}
+
+	m := ParseMeta(string(body))
+	l.Title, l.Description, l.Site, l.Author, l.Tags = m.Title, m.Description, m.Site, m.Author, m.Tags
+	if l.Title == "" {
+		l.Title = rawURL
+	}
+	return l, nil
+}
+
+// FindURLs returns unique http(s) URLs in text.
+func FindURLs(text string) []string {
+	var urls []string
+	seen := make(map[string]bool)
+	for _, u := range urlRgxp.FindAllString(text, -1) {
+		u = strings.TrimRight(u, ".,;:!?)]}")
+		if !seen[u] {
+			seen[u] = true
+			urls = append(urls, u)
+		}
+	}
+	return urls
+}
+
+// DevtoPath returns 'username/slug' path of DEV.TO article URL.
+func DevtoPath(rawURL string) (string, bool) {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Host != "dev.to" && u.Host != "www.dev.to") {
+		return "", false
+	}
+	match := devtoPathRgxp.FindStringSubmatch(u.Path)
+	if match == nil || strings.HasPrefix(match[1], "t/") {
+		return "", false
+	}
+	return match[1], true
+}
@@ -0,0 +1,158 @@
+package link
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/alebsys/telegram-article-bot/internal/devto"
+)
+
+func TestParseMeta(t *testing.T) {
+	page := `<html><head>
+<title>  Fallback
+ title </title>
+<meta name="description" content="plain description">
+<meta property="og:title" content="Tom &amp; Jerry">
+<meta content='Cats and mice' property='og:description'>
+<meta property="og:site_name" content="Cartoons">
+<meta property="article:tag" content="cats"><meta property="article:tag" content="mice">
+<meta name="keywords" content="ignored, keywords">
+</head></html>`
+
+	got := ParseMeta(page)
+	want := Meta{Title: "Tom & Jerry", Description: "Cats and mice", Site: "Cartoons", Tags: []string{"cats", "mice"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseMeta: got %+v; want %+v", got, want)
+	}
+
+	got = ParseMeta(`<title>Only title</title><meta name="description" content="d"><meta name="keywords" content="a, b">`)
+	want = Meta{Title: "Only title", Description: "d", Tags: []string{"a", "b"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseMeta: fallback; got %+v; want %+v", got, want)
+	}
+}
+
+func TestFindURLs(t *testing.T) {
+	got := FindURLs("see https://dev.to/ben/a, and (https://example.com/x?y=1). https://dev.to/ben/a")
+	want := []string{"https://dev.to/ben/a", "https://example.com/x?y=1"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FindURLs: got %v; want %v", got, want)
+	}
+}
+
+func TestDevtoPath(t *testing.T) {
+	cases := []struct {
+		url  string
+		path string
+		ok   bool
+	}{
+		{"https://dev.to/ben/hello-1a2b", "ben/hello-1a2b", true},
+		{"https://www.dev.to/ben/hello/", "ben/hello", true},
+		{"https://dev.to/t/go", "", false},
+		{"https://dev.to/ben", "", false},
+		{"https://example.com/ben/hello", "", false},
+	}
+	for _, c := range cases {
+		path, ok := DevtoPath(c.url)
+		if path != c.path || ok != c.ok {
+			t.Errorf("DevtoPath: %s; got %q, %v; want %q, %v", c.url, path, ok, c.path, c.ok)
+		}
+	}
+}
+
+func TestResolveWeb(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<meta property="og:title" content="Big page">` + strings.Repeat(" ", 1024) + `<meta property="og:description" content="cut off">`))
+	}))
+	defer srv.Close()
+	r := NewResolver(devto.NewClient(devto.WithBaseURL(srv.URL)), nil, 512)
+	r.public = func(ip net.IP) bool { return ip.IsLoopback() }
+
+	l, err := r.Resolve(srv.URL + "/page")
+	if err != nil {
+		t.Fatalf("Resolve: got error %v", err)
+	}
+	if l.Title != "Big page" || l.Description != "" || l.Source != SourceWeb {
+		t.Errorf("Resolve: got %+v; want title only, page is cut off by size limit", l)
+	}
+}
+
+func TestResolveWebPrivate(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/redirect" {
+			http.Redirect(w, r, "http://10.0.0.1/admin", http.StatusFound)
+			return
+		}
+		w.Write([]byte(`<title>Internal</title>`))
+	}))
+	defer srv.Close()
+	port := srv.URL[strings.LastIndex(srv.URL, ":"):]
+
+	r := NewResolver(devto.NewClient(devto.WithBaseURL(srv.URL)), nil, 0)
+	for _, u := range []string{srv.URL + "/page", "http://localhost" + port + "/page", "http://[::1]" + port + "/page", "file:///etc/passwd"} {
+		if l, err := r.Resolve(u); err == nil {
+			t.Errorf("Resolve: %s; got %+v; want error", u, l)
+		}
+	}
+
+	r.public = func(ip net.IP) bool { return ip.IsLoopback() }
+	if l, err := r.Resolve(srv.URL + "/redirect"); err == nil {
+		t.Errorf("Resolve: redirect to private address; got %+v; want error", l)
+	}
+}
+
+func TestGuard(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	tr := http.DefaultTransport.(*http.Transport).Clone()
+	tr.Proxy = nil
+	guard(tr, Public)
+	client := &http.Client{Transport: tr}
+	if resp, err := client.Get(srv.URL); err == nil {
+		resp.Body.Close()
+		t.Errorf("guard: got response from %s; want error", srv.URL)
+	}
+
+	tr = http.DefaultTransport.(*http.Transport).Clone()
+	tr.Proxy = func(*http.Request) (*url.URL, error) { return url.Parse(srv.URL) }
+	guard(tr, Public)
+	client = &http.Client{Transport: tr}
+	resp, err := client.Get("http://example.com/")
+	if err != nil {
+		t.Fatalf("guard: proxy %s; got error %v", srv.URL, err)
+	}
+	resp.Body.Close()
+}
+
+func TestPublic(t *testing.T) {
+	cases := []struct {
+		ip   string
+		want bool
+	}{
+		{"93.184.216.34", true},
+		{"2606:2800:220:1:248:1893:25c8:1946", true},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"10.1.2.3", false},
+		{"192.168.0.1", false},
+		{"172.16.0.1", false},
+		{"fd00::1", false},
+		{"169.254.169.254", false},
+		{"fe80::1", false},
+		{"0.0.0.0", false},
+		{"100.64.0.1", false},
+		{"::ffff:127.0.0.1", false},
+	}
+	for _, c := range cases {
+		if got := Public(net.ParseIP(c.ip)); got != c.want {
+			t.Errorf("Public: %s; got %v; want %v", c.ip, got, c.want)
+		}
+	}
+}
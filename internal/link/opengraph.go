@@ -0,0 +1,80 @@
+package link
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+var (
+	metaRgxp  = regexp.MustCompile(`(?is)<meta\s[^>]*>`)
+	attrRgxp  = regexp.MustCompile(`(?is)([a-z][a-z0-9:_-]*)\s*=\s*(?:"([^"]*)"|'([^']*)'|([^\s"'>]+))`)
+	titleRgxp = regexp.MustCompile(`(?is)<title[^>]*>(.*?)</title>`)
+)
+
+// Meta is metadata of a web page from its OpenGraph and standard meta tags.
+type Meta struct {
+	Title       string
+	Description string
+	Site        string
+	URL         string
+	Author      string
+	Tags        []string
+}
+
+// ParseMeta extracts metadata from HTML page. OpenGraph tags take precedence over standard ones.
+func ParseMeta(page string) Meta {
+	var m Meta
+	var title, description string
+
+	for _, tag := range metaRgxp.FindAllString(page, -1) {
+		attrs := make(map[string]string)
+		for _, a := range attrRgxp.FindAllStringSubmatch(tag, -1) {
+			attrs[strings.ToLower(a[1])] = html.UnescapeString(a[2] + a[3] + a[4])
+		}
+		name := attrs["property"]
+		if name == "" {
+			name = attrs["name"]
+		}
+		content := strings.TrimSpace(attrs["content"])
+		if content == "" {
+			continue
+		}
+
+		switch strings.ToLower(name) {
+		case "og:title":
+			m.Title = content
+		case "og:description":
+			m.Description = content
+		case "og:site_name":
+			m.Site = content
+		case "og:url":
+			m.URL = content
+		case "article:author", "author":
+			m.Author = content
+		case "article:tag":
+			m.Tags = append(m.Tags, content)
+		case "keywords":
+			if len(m.Tags) == 0 {
+				for _, k := range strings.Split(content, ",") {
+					if k = strings.TrimSpace(k); k != "" {
+						m.Tags = append(m.Tags, k)
+					}
+				}
+			}
+		case "description":
+			description = content
+		}
+	}
+
+	if match := titleRgxp.FindStringSubmatch(page); match != nil {
+		title = strings.Join(strings.Fields(html.UnescapeString(match[1])), " ")
+	}
+	if m.Title == "" {
+		m.Title = title
+	}
+	if m.Description == "" {
+		m.Description = description
+	}
+	return m
+}
@@ -0,0 +1,94 @@
+package link
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alebsys/telegram-article-bot/internal/store"
+)
+
+const pendingBucket = "links"
+
+// Pending keeps links sent by users until they choose what to do with them.
+type Pending struct {
+	store store.Store
+}
+
+// NewPending returns Pending backed by s.
+func NewPending(s store.Store) *Pending {
+	return &Pending{store: s}
+}
+
+// Put saves link sent by user and returns its id.
+func (p *Pending) Put(userID int64, l Link) (string, error) {
+	id := l.ID()
+	return id, p.store.Put(pendingBucket, pendingKey(userID, id), l)
+}
+
+// Get returns link sent by user by its id.
+func (p *Pending) Get(userID int64, id string) (Link, bool, error) {
+	var l Link
+	ok, err := p.store.Get(pendingBucket, pendingKey(userID, id), &l)
+	return l, ok, err
+}
+
+// Purge removes links resolved before t.
+func (p *Pending) Purge(before time.Time) error {
+	keys, err := p.store.Keys(pendingBucket)
+	if err != nil {
+		return err
+	}
+	for _, k := range keys {
+		var l Link
+		if _, err := p.store.Get(pendingBucket, k, &l); err != nil {
+			return err
+		}
+		if l.Resolved.Before(before) {
+			if err = p.store.Delete(pendingBucket, k); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Export returns links sent by user.
+func (p *Pending) Export(userID int64) (interface{}, error) {
+	var links []Link
+	err := p.each(userID, func(k string) error {
+		var l Link
+		_, err := p.store.Get(pendingBucket, k, &l)
+		links = append(links, l)
+		return err
+	})
+	return links, err
+}
+
+// Forget removes links sent by user.
+func (p *Pending) Forget(userID int64) error {
+	return p.each(userID, func(k string) error {
+		return p.store.Delete(pendingBucket, k)
+	})
+}
+
+func (p *Pending) each(userID int64, fn func(key string) error) error {
+	keys, err := p.store.Keys(pendingBucket)
+	if err != nil {
+		return err
+	}
+	prefix := pendingKey(userID, "")
+	for _, k := range keys {
+		if !strings.HasPrefix(k, prefix) {
+			continue
+		}
+		if err = fn(k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func pendingKey(userID int64, id string) string {
+	return strconv.FormatInt(userID, 10) + ":" + id
+}
@@ -0,0 +1,220 @@
+package moderation
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/alebsys/telegram-article-bot/internal/devto"
+	"github.com/alebsys/telegram-article-bot/internal/store"
+)
+
+const bucket = "moderation"
+
+// Kinds of blocked things.
+const (
+	Word   = "word"
+	Tag    = "tag"
+	Author = "author"
+)
+
+// unsafeTags and unsafeWords exclude articles in safe for work mode.
+var (
+	unsafeTags  = []string{"nsfw", "adult", "gambling"}
+	unsafeWords = []string{"nsfw", "porn", "xxx", "sex", "onlyfans", "casino"}
+)
+
+// Rules are what a chat doesn't want to see.
+type Rules struct {
+	Words   []string `json:"words,omitempty"`
+	Tags    []string `json:"tags,omitempty"`
+	Authors []string `json:"authors,omitempty"`
+	// SFW excludes articles which are not safe for work.
+	SFW bool `json:"sfw,omitempty"`
+}
+
+// Empty reports whether rules exclude nothing.
+func (r Rules) Empty() bool {
+	return len(r.Words) == 0 && len(r.Tags) == 0 && len(r.Authors) == 0 && !r.SFW
+}
+
+// Clone returns a copy of r which doesn't share lists with r.
+func (r Rules) Clone() Rules {
+	r.Words = append([]string(nil), r.Words...)
+	r.Tags = append([]string(nil), r.Tags...)
+	r.Authors = append([]string(nil), r.Authors...)
+	return r
+}
+
+// Add blocks values of kind, values are lower cased. It reports whether kind is known.
+func (r *Rules) Add(kind string, values ...string) bool {
+	list := r.list(kind)
+	if list == nil {
+		return false
+	}
+	for _, v := range values {
+		if v = normalize(kind, v); v != "" && !contains(*list, v) {
+			*list = append(*list, v)
+		}
+	}
+	sort.Strings(*list)
+	return true
+}
+
+// Remove unblocks values of kind. It reports whether kind is known.
+func (r *Rules) Remove(kind string, values ...string) bool {
+	list := r.list(kind)
+	if list == nil {
+		return false
+	}
+	for _, v := range values {
+		v = normalize(kind, v)
+		for i, old := range *list {
+			if old == v {
+				*list = append((*list)[:i], (*list)[i+1:]...)
+				break
+			}
+		}
+	}
+	return true
+}
+
+func (r *Rules) list(kind string) *[]string {
+	switch kind {
+	case Word:
+		return &r.Words
+	case Tag:
+		return &r.Tags
+	case Author:
+		return &r.Authors
+	}
+	return nil
+}
+
+// Check returns the rule which excludes article and reports whether there is one.
+func (r Rules) Check(a devto.Article) (string, bool) {
+	for _, tag := range a.Tags {
+		if contains(r.Tags, strings.ToLower(tag)) {
+			return fmt.Sprintf("blocked tag #%s", tag), true
+		}
+	}
+	if contains(r.Authors, strings.ToLower(a.User.Username)) {
+		return fmt.Sprintf("blocked author @%s", a.User.Username), true
+	}
+	text := a.Title + " " + a.Description
+	for _, w := range r.Words {
+		if hasWord(text, w) {
+			return fmt.Sprintf("blocked word %q", w), true
+		}
+	}
+	if r.SFW {
+		for _, tag := range a.Tags {
+			if contains(unsafeTags, strings.ToLower(tag)) {
+				return fmt.Sprintf("safe for work mode, tag #%s", tag), true
+			}
+		}
+		for _, w := range unsafeWords {
+			if hasWord(text, w) {
+				return fmt.Sprintf("safe for work mode, word %q", w), true
+			}
+		}
+	}
+	return "", false
+}
+
+// Filter returns articles not excluded by rules.
+func (r Rules) Filter(articles devto.Articles) devto.Articles {
+	if r.Empty() {
+		return articles
+	}
+	kept := make(devto.Articles, 0, len(articles))
+	for _, a := range articles {
+		if _, excluded := r.Check(a); !excluded {
+			kept = append(kept, a)
+		}
+	}
+	return kept
+}
+
+// hasWord reports whether text has word or phrase w as a whole, ignoring case.
+func hasWord(text, w string) bool {
+	re, err := regexp.Compile(`(?i)(^|\W)` + regexp.QuoteMeta(w) + `($|\W)`)
+	return err == nil && re.MatchString(text)
+}
+
+func normalize(kind, v string) string {
+	v = strings.ToLower(strings.TrimSpace(v))
+	switch kind {
+	case Tag:
+		v = strings.TrimPrefix(v, "#")
+	case Author:
+		v = strings.TrimPrefix(v, "@")
+	}
+	return v
+}
+
+func contains(list []string, v string) bool {
+	for _, s := range list {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
+// Moderation keeps rules of chats.
+type Moderation struct {
+	mu    sync.Mutex
+	store store.Store
+}
+
+// New returns Moderation backed by s.
+func New(s store.Store) *Moderation {
+	return &Moderation{store: s}
+}
+
+// Get returns rules of chat.
+func (m *Moderation) Get(chatID int64) (Rules, error) {
+	var r Rules
+	if _, err := m.store.Get(bucket, key(chatID), &r); err != nil {
+		return r, fmt.Errorf("error when reads rules of %d: %v", chatID, err)
+	}
+	return r, nil
+}
+
+// Update changes rules of chat with fn and saves them.
+func (m *Moderation) Update(chatID int64, fn func(r *Rules)) (Rules, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	r, err := m.Get(chatID)
+	if err != nil {
+		return r, err
+	}
+	fn(&r)
+	if r.Empty() {
+		return r, m.store.Delete(bucket, key(chatID))
+	}
+	return r, m.store.Put(bucket, key(chatID), r)
+}
+
+// Export returns rules of user private chat.
+func (m *Moderation) Export(userID int64) (interface{}, error) {
+	r, err := m.Get(userID)
+	if err != nil || r.Empty() {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Forget removes rules of user private chat.
+func (m *Moderation) Forget(userID int64) error {
+	return m.store.Delete(bucket, key(userID))
+}
+
+func key(chatID int64) string {
+	return strconv.FormatInt(chatID, 10)
+}
@@ -0,0 +1,61 @@
+package moderation
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/alebsys/telegram-article-bot/internal/devto"
+	"github.com/alebsys/telegram-article-bot/internal/store"
+)
+
+func TestCheck(t *testing.T) {
+	var r Rules
+	r.Add(Word, "Crypto", "web3")
+	r.Add(Tag, "#Blockchain")
+	r.Add(Author, "@Spammer")
+	r.SFW = true
+
+	cases := []struct {
+		name    string
+		article devto.Article
+		reason  string
+	}{
+		{"clean", devto.Article{Title: "Goroutines explained", Tags: devto.Tags{"go"}}, ""},
+		{"word in title", devto.Article{Title: "Why crypto matters"}, `blocked word "crypto"`},
+		{"word in description", devto.Article{Title: "News", Description: "All about Web3!"}, `blocked word "web3"`},
+		{"part of word", devto.Article{Title: "Cryptography basics"}, ""},
+		{"tag", devto.Article{Tags: devto.Tags{"go", "blockchain"}}, "blocked tag #blockchain"},
+		{"author", devto.Article{User: devto.User{Username: "spammer"}}, "blocked author @spammer"},
+		{"not safe for work", devto.Article{Title: "Best casino apps"}, `safe for work mode, word "casino"`},
+	}
+	for _, c := range cases {
+		reason, excluded := r.Check(c.article)
+		if reason != c.reason || excluded != (c.reason != "") {
+			t.Errorf("Check: %s; got %q, %v; want %q", c.name, reason, excluded, c.reason)
+		}
+	}
+
+	articles := devto.Articles{{ID: 1, Title: "Go"}, {ID: 2, Title: "crypto"}}
+	if got := r.Filter(articles); len(got) != 1 || got[0].ID != 1 {
+		t.Errorf("Filter: got %v; want article 1", got)
+	}
+}
+
+func TestModeration(t *testing.T) {
+	m := New(store.NewMemory())
+
+	r, err := m.Update(-100, func(r *Rules) { r.Add(Tag, "crypto", "nft") })
+	if err != nil {
+		t.Fatalf("Update: got error %v", err)
+	}
+	if !reflect.DeepEqual(r.Tags, []string{"crypto", "nft"}) {
+		t.Errorf("Update: got tags %v", r.Tags)
+	}
+	m.Update(-100, func(r *Rules) { r.Remove(Tag, "#crypto") })
+	if r, _ = m.Get(-100); !reflect.DeepEqual(r.Tags, []string{"nft"}) {
+		t.Errorf("Get: got tags %v; want [nft]", r.Tags)
+	}
+	if ok := r.Add("color", "red"); ok {
+		t.Errorf("Add: unknown kind; got ok")
+	}
+}
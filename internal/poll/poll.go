@@ -0,0 +1,363 @@
+package poll
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/alebsys/telegram-article-bot/internal/store"
+)
+
+const (
+	pollsBucket     = "polls"
+	schedulesBucket = "poll_schedules"
+	chatsBucket     = "poll_chats"
+)
+
+// Candidate is an article offered in a poll.
+type Candidate struct {
+	ArticleID int    `json:"article_id"`
+	Title     string `json:"title"`
+	URL       string `json:"url"`
+}
+
+// Poll is an "article of the week" poll sent to a chat.
+type Poll struct {
+	ID         string           `json:"id"`
+	ChatID     int64            `json:"chat_id"`
+	MessageID  int              `json:"message_id"`
+	Candidates []Candidate      `json:"candidates"`
+	Deadline   time.Time        `json:"deadline"`
+	Answers    map[string][]int `json:"answers,omitempty"`
+	Closed     bool             `json:"closed,omitempty"`
+	// Winner is set when the poll is closed with votes.
+	Winner *Winner `json:"winner,omitempty"`
+}
+
+// Winner is the candidate which got the most votes in a closed poll.
+type Winner struct {
+	Candidate
+	Votes  int       `json:"votes"`
+	Week   string    `json:"week"`
+	Closed time.Time `json:"closed"`
+}
+
+// Schedule makes a chat start a poll every week.
+type Schedule struct {
+	ChatID     int64         `json:"chat_id"`
+	Weekday    time.Weekday  `json:"weekday"`
+	Hour       int           `json:"hour"`
+	Candidates int           `json:"candidates"`
+	Duration   time.Duration `json:"duration"`
+	JobID      string        `json:"job_id,omitempty"`
+}
+
+// Participation is how often a user votes in polls of a chat.
+type Participation struct {
+	Polls    int       `json:"polls"`
+	LastVote time.Time `json:"last_vote"`
+}
+
+// Chat is the archive of winners and participation stats of a chat.
+type Chat struct {
+	Winners []Winner                 `json:"winners,omitempty"`
+	Stats   map[string]Participation `json:"stats,omitempty"`
+}
+
+// Polls keeps polls, their schedules and results.
+type Polls struct {
+	mu    sync.Mutex
+	store store.Store
+}
+
+// New returns Polls backed by s.
+func New(s store.Store) *Polls {
+	return &Polls{store: s}
+}
+
+// Start saves a poll which has just been sent.
+func (p *Polls) Start(poll Poll) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.store.Put(pollsBucket, poll.ID, poll)
+}
+
+// Get returns poll by Telegram poll id.
+func (p *Polls) Get(id string) (Poll, bool, error) {
+	var poll Poll
+	ok, err := p.store.Get(pollsBucket, id, &poll)
+	return poll, ok, err
+}
+
+// Answer records options chosen by user, an empty options retracts the vote.
+// It reports false if the poll is unknown or closed.
+func (p *Polls) Answer(id string, userID int64, options []int, now time.Time) (bool, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var poll Poll
+	ok, err := p.store.Get(pollsBucket, id, &poll)
+	if err != nil || !ok || poll.Closed {
+		return false, err
+	}
+
+	user := strconv.FormatInt(userID, 10)
+	_, voted := poll.Answers[user]
+	if poll.Answers == nil {
+		poll.Answers = make(map[string][]int)
+	}
+	if len(options) == 0 {
+		delete(poll.Answers, user)
+	} else {
+		poll.Answers[user] = options
+	}
+	if err = p.store.Put(pollsBucket, id, poll); err != nil {
+		return false, err
+	}
+
+	chat, err := p.chat(poll.ChatID)
+	if err != nil {
+		return false, err
+	}
+	if chat.Stats == nil {
+		chat.Stats = make(map[string]Participation)
+	}
+	stats := chat.Stats[user]
+	switch {
+	case !voted && len(options) > 0:
+		stats.Polls++
+		stats.LastVote = now
+	case voted && len(options) == 0:
+		stats.Polls--
+	default:
+		stats.LastVote = now
+	}
+	if stats.Polls > 0 {
+		chat.Stats[user] = stats
+	} else {
+		delete(chat.Stats, user)
+	}
+	return true, p.store.Put(chatsBucket, key(poll.ChatID), chat)
+}
+
+// Close marks poll as closed and archives its winner. It reports false if the poll had no votes.
+// Closing a closed poll returns its winner again, so the announcement can be retried.
+func (p *Polls) Close(id string, now time.Time) (Winner, bool, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var poll Poll
+	ok, err := p.store.Get(pollsBucket, id, &poll)
+	if err != nil {
+		return Winner{}, false, err
+	}
+	if !ok {
+		return Winner{}, false, fmt.Errorf("poll %s is unknown", id)
+	}
+	if poll.Closed {
+		if poll.Winner == nil {
+			return Winner{}, false, nil
+		}
+		return *poll.Winner, true, nil
+	}
+
+	poll.Closed = true
+	b := &store.Batch{}
+	winner, ok := poll.winner()
+	if ok {
+		year, week := now.ISOWeek()
+		winner.Week = fmt.Sprintf("%d-W%02d", year, week)
+		winner.Closed = now
+		poll.Winner = &winner
+
+		chat, err := p.chat(poll.ChatID)
+		if err != nil {
+			return Winner{}, false, err
+		}
+		chat.Winners = append(chat.Winners, winner)
+		b.Put(chatsBucket, key(poll.ChatID), chat)
+	}
+	b.Put(pollsBucket, id, poll)
+	return winner, ok, store.Apply(p.store, b)
+}
+
+// Chat returns winners and participation stats of chat.
+func (p *Polls) Chat(chatID int64) (*Chat, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.chat(chatID)
+}
+
+// Schedule returns weekly poll schedule of chat.
+func (p *Polls) Schedule(chatID int64) (Schedule, bool, error) {
+	var s Schedule
+	ok, err := p.store.Get(schedulesBucket, key(chatID), &s)
+	return s, ok, err
+}
+
+// SetSchedule saves weekly poll schedule of chat.
+func (p *Polls) SetSchedule(s Schedule) error {
+	return p.store.Put(schedulesBucket, key(s.ChatID), s)
+}
+
+// DeleteSchedule removes weekly poll schedule of chat.
+func (p *Polls) DeleteSchedule(chatID int64) error {
+	return p.store.Delete(schedulesBucket, key(chatID))
+}
+
+// Export returns answers and participation stats of user in every chat.
+func (p *Polls) Export(userID int64) (interface{}, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	user := strconv.FormatInt(userID, 10)
+	type answer struct {
+		PollID  string      `json:"poll_id"`
+		ChatID  int64       `json:"chat_id"`
+		Options []Candidate `json:"options"`
+	}
+	export := struct {
+		Answers []answer                 `json:"answers,omitempty"`
+		Stats   map[string]Participation `json:"stats,omitempty"`
+	}{Stats: make(map[string]Participation)}
+
+	err := p.eachPoll(func(poll *Poll) bool {
+		if options, ok := poll.Answers[user]; ok {
+			a := answer{PollID: poll.ID, ChatID: poll.ChatID}
+			for _, o := range options {
+				if o >= 0 && o < len(poll.Candidates) {
+					a.Options = append(a.Options, poll.Candidates[o])
+				}
+			}
+			export.Answers = append(export.Answers, a)
+		}
+		return false
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = p.eachChat(func(chatID string, chat *Chat) bool {
+		if stats, ok := chat.Stats[user]; ok {
+			export.Stats[chatID] = stats
+		}
+		return false
+	})
+	return export, err
+}
+
+// Forget removes answers and participation stats of user in every chat.
+func (p *Polls) Forget(userID int64) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	user := strconv.FormatInt(userID, 10)
+	err := p.eachPoll(func(poll *Poll) bool {
+		if _, ok := poll.Answers[user]; !ok {
+			return false
+		}
+		delete(poll.Answers, user)
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	return p.eachChat(func(_ string, chat *Chat) bool {
+		if _, ok := chat.Stats[user]; !ok {
+			return false
+		}
+		delete(chat.Stats, user)
+		return true
+	})
+}
+
+func (p *Polls) eachPoll(fn func(poll *Poll) bool) error {
+	keys, err := p.store.Keys(pollsBucket)
+	if err != nil {
+		return err
+	}
+	for _, k := range keys {
+		var poll Poll
+		if _, err := p.store.Get(pollsBucket, k, &poll); err != nil {
+			return err
+		}
+		if fn(&poll) {
+			if err := p.store.Put(pollsBucket, k, poll); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (p *Polls) eachChat(fn func(chatID string, chat *Chat) bool) error {
+	keys, err := p.store.Keys(chatsBucket)
+	if err != nil {
+		return err
+	}
+	for _, k := range keys {
+		chat := new(Chat)
+		if _, err := p.store.Get(chatsBucket, k, chat); err != nil {
+			return err
+		}
+		if fn(k, chat) {
+			if err := p.store.Put(chatsBucket, k, chat); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (p *Polls) chat(chatID int64) (*Chat, error) {
+	chat := new(Chat)
+	if _, err := p.store.Get(chatsBucket, key(chatID), chat); err != nil {
+		return nil, fmt.Errorf("error when reads polls of %d: %v", chatID, err)
+	}
+	return chat, nil
+}
+
+// winner counts answers and returns the most voted candidate, the higher placed candidate wins a tie.
+func (poll *Poll) winner() (Winner, bool) {
+	votes := make([]int, len(poll.Candidates))
+	for _, options := range poll.Answers {
+		for _, o := range options {
+			if o >= 0 && o < len(votes) {
+				votes[o]++
+			}
+		}
+	}
+
+	best := -1
+	for i, v := range votes {
+		if v > 0 && (best < 0 || v > votes[best]) {
+			best = i
+		}
+	}
+	if best < 0 {
+		return Winner{}, false
+	}
+	return Winner{Candidate: poll.Candidates[best], Votes: votes[best]}, true
+}
+
+// TopVoters returns user ids of chat sorted by the number of polls they voted in.
+func (c *Chat) TopVoters() []string {
+	users := make([]string, 0, len(c.Stats))
+	for u := range c.Stats {
+		users = append(users, u)
+	}
+	sort.Slice(users, func(i, j int) bool {
+		if c.Stats[users[i]].Polls != c.Stats[users[j]].Polls {
+			return c.Stats[users[i]].Polls > c.Stats[users[j]].Polls
+		}
+		return users[i] < users[j]
+	})
+	return users
+}
+
+func key(chatID int64) string {
+	return strconv.FormatInt(chatID, 10)
+}
@@ -0,0 +1,90 @@
+package poll
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alebsys/telegram-article-bot/internal/store"
+)
+
+func TestAnswerAndClose(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	polls := New(store.NewMemory())
+	polls.Start(Poll{
+		ID:     "p1",
+		ChatID: 1,
+		Candidates: []Candidate{
+			{ArticleID: 10, Title: "first"},
+			{ArticleID: 20, Title: "second"},
+			{ArticleID: 30, Title: "third"},
+		},
+	})
+
+	answers := []struct {
+		userID  int64
+		options []int
+	}{
+		{100, []int{1}},
+		{200, []int{0}},
+		{300, []int{1}},
+		{300, nil},
+		{300, []int{2}},
+		{400, []int{1}},
+	}
+	for _, a := range answers {
+		if ok, err := polls.Answer("p1", a.userID, a.options, now); !ok || err != nil {
+			t.Fatalf("Answer: got %v, %v", ok, err)
+		}
+	}
+	if ok, _ := polls.Answer("unknown", 100, []int{0}, now); ok {
+		t.Errorf("Answer: unknown poll accepted")
+	}
+
+	winner, ok, err := polls.Close("p1", now)
+	if err != nil || !ok {
+		t.Fatalf("Close: got %v, %v", ok, err)
+	}
+	if winner.ArticleID != 20 || winner.Votes != 2 || winner.Week != "2026-W42" {
+		t.Errorf("Close: got %+v; want article 20 with 2 votes in 2026-W42", winner)
+	}
+	if ok, _ := polls.Answer("p1", 500, []int{0}, now); ok {
+		t.Errorf("Answer: closed poll accepted")
+	}
+	if again, ok, err := polls.Close("p1", now.Add(time.Hour)); err != nil || !ok || again.ArticleID != winner.ArticleID || !again.Closed.Equal(winner.Closed) {
+		t.Errorf("Close: closed again; got %+v, %v, %v; want the same winner", again, ok, err)
+	}
+	if _, _, err = polls.Close("unknown", now); err == nil {
+		t.Errorf("Close: unknown poll; got no error")
+	}
+
+	chat, _ := polls.Chat(1)
+	if len(chat.Winners) != 1 {
+		t.Errorf("Chat: got %d winners; want 1", len(chat.Winners))
+	}
+	for _, user := range []string{"100", "200", "300", "400"} {
+		if chat.Stats[user].Polls != 1 {
+			t.Errorf("Chat: user %s voted in %d polls; want 1", user, chat.Stats[user].Polls)
+		}
+	}
+
+	if err = polls.Forget(300); err != nil {
+		t.Fatalf("Forget: got error %v", err)
+	}
+	chat, _ = polls.Chat(1)
+	poll, _, _ := polls.Get("p1")
+	if _, ok := chat.Stats["300"]; ok {
+		t.Errorf("Forget: stats of user are kept")
+	}
+	if _, ok := poll.Answers["300"]; ok {
+		t.Errorf("Forget: answers of user are kept")
+	}
+}
+
+func TestCloseWithoutVotes(t *testing.T) {
+	polls := New(store.NewMemory())
+	polls.Start(Poll{ID: "p1", ChatID: 1, Candidates: []Candidate{{ArticleID: 10}}})
+
+	if _, ok, err := polls.Close("p1", time.Now()); ok || err != nil {
+		t.Errorf("Close: got %v, %v; want no winner", ok, err)
+	}
+}
@@ -0,0 +1,178 @@
+package profile
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/alebsys/telegram-article-bot/internal/devto"
+	"github.com/alebsys/telegram-article-bot/internal/store"
+)
+
+const (
+	bucket = "profiles"
+
+	// SessionGap is the inactivity after which the next interaction starts a new session.
+	SessionGap = 6 * time.Hour
+	maxSeen    = 1000
+	minBoost   = 0.1
+	maxBoost   = 3
+	boostScale = 0.2
+)
+
+// Profile is what the bot learned about a user.
+type Profile struct {
+	LastSeen time.Time `json:"last_seen"`
+	// PrevSeen is the last interaction before the current session.
+	PrevSeen time.Time            `json:"prev_seen,omitempty"`
+	Seen     map[string]time.Time `json:"seen,omitempty"`
+	Feedback map[string]float64   `json:"feedback,omitempty"`
+}
+
+// Profiles keeps profiles of users.
+type Profiles struct {
+	mu    sync.Mutex
+	store store.Store
+}
+
+// New returns Profiles backed by s.
+func New(s store.Store) *Profiles {
+	return &Profiles{store: s}
+}
+
+// Get returns profile of user.
+func (p *Profiles) Get(userID int64) (*Profile, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.get(userID)
+}
+
+// Touch records interaction of user at now and returns how long the user was away
+// if the interaction starts a new session, zero otherwise.
+func (p *Profiles) Touch(userID int64, now time.Time) (time.Duration, error) {
+	var away time.Duration
+	err := p.update(userID, func(prof *Profile) {
+		if !prof.LastSeen.IsZero() && now.Sub(prof.LastSeen) > SessionGap {
+			away = now.Sub(prof.LastSeen)
+			prof.PrevSeen = prof.LastSeen
+		}
+		prof.LastSeen = now
+	})
+	return away, err
+}
+
+// MarkSeen records articles shown to user, only maxSeen latest articles are kept.
+func (p *Profiles) MarkSeen(userID int64, articles devto.Articles, now time.Time) error {
+	if len(articles) == 0 {
+		return nil
+	}
+	return p.update(userID, func(prof *Profile) {
+		if prof.Seen == nil {
+			prof.Seen = make(map[string]time.Time)
+		}
+		for _, a := range articles {
+			prof.Seen[strconv.Itoa(a.ID)] = now
+		}
+		prof.trimSeen()
+	})
+}
+
+// AddFeedback adds weight to every tag in user feedback, negative weight means the user is not interested.
+func (p *Profiles) AddFeedback(userID int64, tags []string, weight float64) error {
+	if len(tags) == 0 {
+		return nil
+	}
+	return p.update(userID, func(prof *Profile) {
+		if prof.Feedback == nil {
+			prof.Feedback = make(map[string]float64)
+		}
+		for _, tag := range tags {
+			prof.Feedback[tag] += weight
+		}
+	})
+}
+
+// Export returns profile of user.
+func (p *Profiles) Export(userID int64) (interface{}, error) {
+	return p.Get(userID)
+}
+
+// Forget removes profile of user.
+func (p *Profiles) Forget(userID int64) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.store.Delete(bucket, key(userID))
+}
+
+func (p *Profiles) get(userID int64) (*Profile, error) {
+	prof := new(Profile)
+	if _, err := p.store.Get(bucket, key(userID), prof); err != nil {
+		return nil, fmt.Errorf("error when reads profile of %d: %v", userID, err)
+	}
+	return prof, nil
+}
+
+func (p *Profiles) update(userID int64, fn func(prof *Profile)) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	prof, err := p.get(userID)
+	if err != nil {
+		return err
+	}
+	fn(prof)
+	return p.store.Put(bucket, key(userID), prof)
+}
+
+// HasSeen reports whether article was shown to user.
+func (prof *Profile) HasSeen(id int) bool {
+	_, ok := prof.Seen[strconv.Itoa(id)]
+	return ok
+}
+
+// Score is article reactions boosted by user feedback on its tags.
+func (prof *Profile) Score(a devto.Article) float64 {
+	var weight float64
+	for _, tag := range a.Tags {
+		weight += prof.Feedback[tag]
+	}
+	boost := 1 + boostScale*weight
+	if boost < minBoost {
+		boost = minBoost
+	}
+	if boost > maxBoost {
+		boost = maxBoost
+	}
+	return float64(a.Score+1) * boost
+}
+
+// Rank sorts articles by Score, the most interesting go first.
+func (prof *Profile) Rank(articles devto.Articles) {
+	sort.SliceStable(articles, func(i, j int) bool {
+		return prof.Score(articles[i]) > prof.Score(articles[j])
+	})
+}
+
+func (prof *Profile) trimSeen() {
+	if len(prof.Seen) <= maxSeen {
+		return
+	}
+	ids := make([]string, 0, len(prof.Seen))
+	for id := range prof.Seen {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return prof.Seen[ids[i]].After(prof.Seen[ids[j]])
+	})
+	for _, id := range ids[maxSeen:] {
+		delete(prof.Seen, id)
+	}
+}
+
+func key(userID int64) string {
+	return strconv.FormatInt(userID, 10)
+}
@@ -0,0 +1,76 @@
+package profile
+
+import (
+	"testing"
+	"time"
+
+	"github.com/alebsys/telegram-article-bot/internal/devto"
+	"github.com/alebsys/telegram-article-bot/internal/store"
+)
+
+func TestTouch(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	p := New(store.NewMemory())
+
+	cases := []struct {
+		name string
+		at   time.Time
+		away time.Duration
+		prev time.Time
+	}{
+		{"first interaction", now, 0, time.Time{}},
+		{"same session", now.Add(time.Hour), 0, time.Time{}},
+		{"back after days", now.Add(73 * time.Hour), 72 * time.Hour, now.Add(time.Hour)},
+	}
+	for _, c := range cases {
+		away, err := p.Touch(1, c.at)
+		if err != nil {
+			t.Fatalf("Touch: %s; got error %v", c.name, err)
+		}
+		prof, _ := p.Get(1)
+		if away != c.away || !prof.PrevSeen.Equal(c.prev) || !prof.LastSeen.Equal(c.at) {
+			t.Errorf("Touch: %s; got away %v, profile %+v", c.name, away, prof)
+		}
+	}
+}
+
+func TestMarkSeen(t *testing.T) {
+	now := time.Now()
+	p := New(store.NewMemory())
+
+	var articles devto.Articles
+	for i := 1; i <= maxSeen+10; i++ {
+		articles = append(articles, devto.Article{ID: i})
+	}
+	p.MarkSeen(1, articles[:10], now)
+	p.MarkSeen(1, articles[10:], now.Add(time.Minute))
+
+	prof, _ := p.Get(1)
+	if len(prof.Seen) != maxSeen {
+		t.Errorf("MarkSeen: got %d seen; want %d", len(prof.Seen), maxSeen)
+	}
+	if prof.HasSeen(1) || !prof.HasSeen(maxSeen+10) {
+		t.Errorf("MarkSeen: the oldest articles must be dropped first")
+	}
+}
+
+func TestRank(t *testing.T) {
+	p := New(store.NewMemory())
+	p.AddFeedback(1, []string{"go"}, 5)
+	p.AddFeedback(1, []string{"javascript"}, -10)
+	prof, _ := p.Get(1)
+
+	articles := devto.Articles{
+		{ID: 1, Score: 100, Tags: devto.Tags{"javascript"}},
+		{ID: 2, Score: 40, Tags: devto.Tags{"go"}},
+		{ID: 3, Score: 60, Tags: devto.Tags{"rust"}},
+	}
+	prof.Rank(articles)
+
+	want := []int{2, 3, 1}
+	for i, a := range articles {
+		if a.ID != want[i] {
+			t.Errorf("Rank: position %d; got %d; want %d", i, a.ID, want[i])
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReserve(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	l := New(2, 2)
+
+	cases := []struct {
+		name  string
+		after time.Duration
+		want  time.Duration
+	}{
+		{"burst", 0, 0},
+		{"burst", 0, 0},
+		{"over burst", 0, 500 * time.Millisecond},
+		{"still waiting", 0, time.Second},
+		{"refilled", 3 * time.Second, 0},
+	}
+	for _, c := range cases {
+		now = now.Add(c.after)
+		if got := l.Reserve(now); got != c.want {
+			t.Errorf("Reserve: %s; got %v; want %v", c.name, got, c.want)
+		}
+	}
+}
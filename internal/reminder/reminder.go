@@ -0,0 +1,151 @@
+package reminder
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/alebsys/telegram-article-bot/internal/store"
+)
+
+const bucket = "reminders"
+
+// Clock is a time of day.
+type Clock struct {
+	Hour   int
+	Minute int
+}
+
+// ParseClock parses time of day in 'HH:MM' format.
+func ParseClock(s string) (Clock, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 2 {
+		return Clock{}, fmt.Errorf("time must be in HH:MM format")
+	}
+	h, err1 := strconv.Atoi(parts[0])
+	m, err2 := strconv.Atoi(parts[1])
+	if err1 != nil || err2 != nil || h < 0 || h > 23 || m < 0 || m > 59 {
+		return Clock{}, fmt.Errorf("time must be in HH:MM format")
+	}
+	return Clock{Hour: h, Minute: m}, nil
+}
+
+// Quiet is a range of hours when nothing is sent. From equal to To means no quiet hours.
+type Quiet struct {
+	From int
+	To   int
+}
+
+// ParseQuiet parses quiet hours in 'HH-HH' format, 'off' disables them.
+func ParseQuiet(s string) (Quiet, error) {
+	if s == "off" {
+		return Quiet{}, nil
+	}
+	parts := strings.Split(s, "-")
+	if len(parts) != 2 {
+		return Quiet{}, fmt.Errorf("quiet hours must be in HH-HH format or off")
+	}
+	from, err1 := strconv.Atoi(parts[0])
+	to, err2 := strconv.Atoi(parts[1])
+	if err1 != nil || err2 != nil || from < 0 || from > 23 || to < 0 || to > 23 {
+		return Quiet{}, fmt.Errorf("quiet hours must be in HH-HH format or off")
+	}
+	return Quiet{From: from, To: to}, nil
+}
+
+// Contains reports whether hour falls into quiet hours, the range may wrap over midnight.
+func (q Quiet) Contains(hour int) bool {
+	if q.From == q.To {
+		return false
+	}
+	if q.From < q.To {
+		return hour >= q.From && hour < q.To
+	}
+	return hour >= q.From || hour < q.To
+}
+
+// Schedule is when a user wants to get daily reminders.
+type Schedule struct {
+	At       Clock
+	Quiet    Quiet
+	Location *time.Location
+}
+
+// Due reports whether reminders have to be sent at now if the last ones were sent at lastSent.
+// Reminders are due once a day since At, those falling into quiet hours are postponed until they end.
+func (s Schedule) Due(now, lastSent time.Time) bool {
+	loc := s.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	local := now.In(loc)
+	if s.Quiet.Contains(local.Hour()) {
+		return false
+	}
+
+	at := time.Date(local.Year(), local.Month(), local.Day(), s.At.Hour, s.At.Minute, 0, 0, loc)
+	if at.After(local) {
+		at = at.AddDate(0, 0, -1)
+	}
+	return lastSent.Before(at)
+}
+
+// Next returns the first At after now. Reminders are sent then unless it falls into quiet hours.
+func (s Schedule) Next(now time.Time) time.Time {
+	loc := s.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	local := now.In(loc)
+	at := time.Date(local.Year(), local.Month(), local.Day(), s.At.Hour, s.At.Minute, 0, 0, loc)
+	if !at.After(local) {
+		at = at.AddDate(0, 0, 1)
+	}
+	return at
+}
+
+// Log keeps when reminders were sent to users.
+type Log struct {
+	mu    sync.Mutex
+	store store.Store
+}
+
+// NewLog returns Log backed by s.
+func NewLog(s store.Store) *Log {
+	return &Log{store: s}
+}
+
+// LastSent returns when reminders were sent to user the last time.
+func (l *Log) LastSent(userID int64) (time.Time, error) {
+	var t time.Time
+	_, err := l.store.Get(bucket, key(userID), &t)
+	return t, err
+}
+
+// Sent records that reminders were sent to user at t.
+func (l *Log) Sent(userID int64, t time.Time) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.store.Put(bucket, key(userID), t)
+}
+
+// Export returns when reminders were sent to user the last time.
+func (l *Log) Export(userID int64) (interface{}, error) {
+	t, err := l.LastSent(userID)
+	if err != nil || t.IsZero() {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Forget removes reminder log of user.
+func (l *Log) Forget(userID int64) error {
+	return l.store.Delete(bucket, key(userID))
+}
+
+func key(userID int64) string {
+	return strconv.FormatInt(userID, 10)
+}
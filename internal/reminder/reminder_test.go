@@ -0,0 +1,94 @@
+package reminder
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	if c, err := ParseClock("07:30"); err != nil || c != (Clock{7, 30}) {
+		t.Errorf("ParseClock: got %v, %v", c, err)
+	}
+	for _, bad := range []string{"7", "24:00", "07:60", "a:b"} {
+		if _, err := ParseClock(bad); err == nil {
+			t.Errorf("ParseClock: %q; got no error", bad)
+		}
+	}
+	if q, err := ParseQuiet("22-08"); err != nil || q != (Quiet{22, 8}) {
+		t.Errorf("ParseQuiet: got %v, %v", q, err)
+	}
+	if q, err := ParseQuiet("off"); err != nil || q.Contains(3) {
+		t.Errorf("ParseQuiet: off; got %v, %v", q, err)
+	}
+	if _, err := ParseQuiet("22"); err == nil {
+		t.Errorf("ParseQuiet: got no error")
+	}
+}
+
+func TestQuietContains(t *testing.T) {
+	cases := []struct {
+		name  string
+		quiet Quiet
+		hour  int
+		want  bool
+	}{
+		{"inside day range", Quiet{13, 15}, 14, true},
+		{"range end", Quiet{13, 15}, 15, false},
+		{"inside night range", Quiet{22, 8}, 3, true},
+		{"night range start", Quiet{22, 8}, 22, true},
+		{"outside night range", Quiet{22, 8}, 12, false},
+	}
+	for _, c := range cases {
+		if got := c.quiet.Contains(c.hour); got != c.want {
+			t.Errorf("Contains: %s; got %v; want %v", c.name, got, c.want)
+		}
+	}
+}
+
+func TestDue(t *testing.T) {
+	day := func(d, h, m int) time.Time { return time.Date(2026, 10, d, h, m, 0, 0, time.UTC) }
+	berlin := time.FixedZone("UTC+2", 2*60*60)
+
+	cases := []struct {
+		name     string
+		schedule Schedule
+		now      time.Time
+		lastSent time.Time
+		want     bool
+	}{
+		{"before time", Schedule{At: Clock{9, 0}}, day(16, 8, 59), day(15, 9, 0), false},
+		{"at time", Schedule{At: Clock{9, 0}}, day(16, 9, 0), day(15, 9, 0), true},
+		{"already sent", Schedule{At: Clock{9, 0}}, day(16, 18, 0), day(16, 9, 5), false},
+		{"never sent", Schedule{At: Clock{9, 0}}, day(16, 1, 0), time.Time{}, true},
+		{"time zone", Schedule{At: Clock{9, 0}, Location: berlin}, day(16, 7, 0), day(15, 9, 0), true},
+		{"quiet hours", Schedule{At: Clock{23, 0}, Quiet: Quiet{22, 8}}, day(16, 23, 0), day(15, 8, 0), false},
+		{"after quiet hours", Schedule{At: Clock{23, 0}, Quiet: Quiet{22, 8}}, day(17, 8, 0), day(15, 8, 0), true},
+	}
+	for _, c := range cases {
+		if got := c.schedule.Due(c.now, c.lastSent); got != c.want {
+			t.Errorf("Due: %s; got %v; want %v", c.name, got, c.want)
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	day := func(d, h, m int) time.Time { return time.Date(2026, 10, d, h, m, 0, 0, time.UTC) }
+	berlin := time.FixedZone("UTC+2", 2*60*60)
+
+	cases := []struct {
+		name     string
+		schedule Schedule
+		now      time.Time
+		want     time.Time
+	}{
+		{"later today", Schedule{At: Clock{9, 0}}, day(16, 8, 0), day(16, 9, 0)},
+		{"at time", Schedule{At: Clock{9, 0}}, day(16, 9, 0), day(17, 9, 0)},
+		{"tomorrow", Schedule{At: Clock{9, 0}}, day(16, 9, 5), day(17, 9, 0)},
+		{"time zone", Schedule{At: Clock{9, 0}, Location: berlin}, day(16, 8, 0), day(17, 7, 0)},
+	}
+	for _, c := range cases {
+		if got := c.schedule.Next(c.now); !got.Equal(c.want) {
+			t.Errorf("Next: %s; got %v; want %v", c.name, got, c.want)
+		}
+	}
+}
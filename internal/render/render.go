@@ -0,0 +1,170 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/alebsys/telegram-article-bot/internal/devto"
+	"github.com/alecthomas/chroma"
+	chromahtml "github.com/alecthomas/chroma/formatters/html"
+	"github.com/alecthomas/chroma/lexers"
+	"github.com/alecthomas/chroma/styles"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/util"
+)
+
+const codeStyle = "github"
+
+var (
+	frontMatterRgxp = regexp.MustCompile(`(?s)\A\s*---\n.*?\n---\s*\n`)
+	liquidTagRgxp   = regexp.MustCompile(`{%\s*(\w+)\s*(.*?)\s*%}`)
+)
+
+var page = template.Must(template.New("article").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<style>
+body { max-width: 42em; margin: 0 auto; padding: 1em; font: 17px/1.6 -apple-system, "Segoe UI", Roboto, sans-serif; color: #1b1b1b; background: #fff; overflow-wrap: break-word; }
+header p { color: #666; margin: 0.2em 0; }
+img, video { max-width: 100%; height: auto; }
+pre { overflow-x: auto; padding: 0.8em; border-radius: 6px; font-size: 14px; line-height: 1.4; }
+code { font-family: "SFMono-Regular", Menlo, Consolas, monospace; background: #f3f3f3; padding: 0.1em 0.3em; border-radius: 4px; }
+pre code { background: none; padding: 0; }
+blockquote { margin: 0; padding-left: 1em; border-left: 4px solid #ddd; color: #555; }
+table { border-collapse: collapse; display: block; overflow-x: auto; }
+th, td { border: 1px solid #ddd; padding: 0.3em 0.6em; }
+{{.CSS}}
+</style>
+</head>
+<body>
+<article>
+<header>
+<h1>{{.Title}}</h1>
+<p>{{.Author}}{{if .Date}} · {{.Date}}{{end}}{{if .ReadingTime}} · {{.ReadingTime}} min read{{end}}</p>
+{{if .Tags}}<p>{{range .Tags}}#{{.}} {{end}}</p>{{end}}
+</header>
+{{.Body}}
+<footer><p><a href="{{.URL}}">Read on DEV.TO</a></p></footer>
+</article>
+</body>
+</html>
+`))
+
+// Article writes a standalone HTML page of article with body in Markdown to w. Raw HTML of the body is omitted
+// and code blocks are highlighted, styles are embedded, so the page can be read offline.
+func Article(w io.Writer, a devto.Article, body string) error {
+	formatter := chromahtml.New(chromahtml.WithClasses(true), chromahtml.TabWidth(4))
+	style := styles.Get(codeStyle)
+
+	md := goldmark.New(
+		goldmark.WithExtensions(extension.GFM),
+		goldmark.WithRendererOptions(renderer.WithNodeRenderers(
+			util.Prioritized(&codeRenderer{formatter: formatter, style: style}, 200),
+		)),
+	)
+	html := new(bytes.Buffer)
+	if err := md.Convert([]byte(Markdown(body)), html); err != nil {
+		return fmt.Errorf("error when converts markdown of article %d: %v", a.ID, err)
+	}
+	css := new(bytes.Buffer)
+	if err := formatter.WriteCSS(css, style); err != nil {
+		return fmt.Errorf("error when writes styles of code: %v", err)
+	}
+
+	author := a.User.Name
+	if a.User.Username != "" {
+		author = fmt.Sprintf("%s (@%s)", a.User.Name, a.User.Username)
+	}
+	var date string
+	if !a.PublishedAt.IsZero() {
+		date = a.PublishedAt.UTC().Format("Jan 2, 2006")
+	}
+	err := page.Execute(w, map[string]interface{}{
+		"Title":       a.Title,
+		"Author":      author,
+		"Date":        date,
+		"ReadingTime": a.ReadingTime,
+		"Tags":        a.Tags,
+		"URL":         a.Url,
+		// styles and the body are made by chroma and goldmark which escape the text of the article
+		"CSS":  template.CSS(css.String()),
+		"Body": template.HTML(html.String()),
+	})
+	if err != nil {
+		return fmt.Errorf("error when writes page of article %d: %v", a.ID, err)
+	}
+	return nil
+}
+
+// Markdown turns DEV.TO Markdown into plain Markdown: it drops front matter and replaces
+// liquid tags of embeds with links, the others are dropped.
+func Markdown(body string) string {
+	body = frontMatterRgxp.ReplaceAllString(body, "")
+	return liquidTagRgxp.ReplaceAllStringFunc(body, func(tag string) string {
+		m := liquidTagRgxp.FindStringSubmatch(tag)
+		name, arg := m[1], strings.Trim(m[2], `"'`)
+		switch {
+		case strings.HasPrefix(arg, "https://") || strings.HasPrefix(arg, "http://"):
+			return "<" + strings.Fields(arg)[0] + ">"
+		case name == "youtube" && arg != "":
+			return "<https://youtu.be/" + arg + ">"
+		case name == "github" && arg != "":
+			return "<https://github.com/" + strings.Fields(arg)[0] + ">"
+		}
+		return ""
+	})
+}
+
+// codeRenderer highlights code blocks with chroma.
+type codeRenderer struct {
+	formatter *chromahtml.Formatter
+	style     *chroma.Style
+}
+
+func (r *codeRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(ast.KindFencedCodeBlock, r.render)
+	reg.Register(ast.KindCodeBlock, r.render)
+}
+
+func (r *codeRenderer) render(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	code := new(strings.Builder)
+	lines := node.Lines()
+	for i := 0; i < lines.Len(); i++ {
+		line := lines.At(i)
+		code.Write(line.Value(source))
+	}
+
+	var lexer chroma.Lexer
+	if fenced, ok := node.(*ast.FencedCodeBlock); ok {
+		if lang := fenced.Language(source); lang != nil {
+			lexer = lexers.Get(string(lang))
+		}
+	}
+	if lexer == nil {
+		lexer = lexers.Analyse(code.String())
+	}
+	if lexer == nil {
+		lexer = lexers.Fallback
+	}
+	tokens, err := chroma.Coalesce(lexer).Tokenise(nil, code.String())
+	if err != nil {
+		return ast.WalkStop, err
+	}
+	if err = r.formatter.Format(w, r.style, tokens); err != nil {
+		return ast.WalkStop, err
+	}
+	return ast.WalkSkipChildren, nil
+}
@@ -0,0 +1,63 @@
+package render
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alebsys/telegram-article-bot/internal/devto"
+)
+
+func TestMarkdown(t *testing.T) {
+	cases := []struct {
+		name string
+		body string
+		want string
+	}{
+		{"plain", "# Hi\n\ntext", "# Hi\n\ntext"},
+		{"front matter", "---\ntitle: Hi\npublished: true\n---\n\ntext", "text"},
+		{"embed", "see {% embed https://go.dev/blog %}", "see <https://go.dev/blog>"},
+		{"youtube", "{% youtube dQw4w9WgXcQ %}", "<https://youtu.be/dQw4w9WgXcQ>"},
+		{"github", "{% github golang/go no-readme %}", "<https://github.com/golang/go>"},
+		{"other tags", "{% raw %}{{x}}{% endraw %}", "{{x}}"},
+	}
+	for _, c := range cases {
+		if got := Markdown(c.body); got != c.want {
+			t.Errorf("Markdown: %s; got %q; want %q", c.name, got, c.want)
+		}
+	}
+}
+
+func TestArticle(t *testing.T) {
+	a := devto.Article{
+		ID:          1,
+		Title:       "Go <generics>",
+		Url:         "https://dev.to/ann/go-generics",
+		ReadingTime: 3,
+		PublishedAt: time.Date(2022, 5, 1, 12, 0, 0, 0, time.UTC),
+		Tags:        devto.Tags{"go"},
+		User:        devto.User{Name: "Ann", Username: "ann"},
+	}
+	body := "Intro <script>alert(1)</script>\n\n```go\nfunc main() {}\n```\n"
+
+	buf := new(bytes.Buffer)
+	if err := Article(buf, a, body); err != nil {
+		t.Fatalf("Article: got error %v", err)
+	}
+	page := buf.String()
+	for _, want := range []string{
+		"<title>Go &lt;generics&gt;</title>",
+		"Ann (@ann) · May 1, 2022 · 3 min read",
+		`<span class="kd">func</span>`,
+		".chroma",
+		`<a href="https://dev.to/ann/go-generics">`,
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("Article: got page without %q", want)
+		}
+	}
+	if strings.Contains(page, "<script>") {
+		t.Errorf("Article: got page with raw HTML of the body")
+	}
+}
@@ -0,0 +1,225 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/alebsys/telegram-article-bot/internal/store"
+)
+
+const (
+	bucket      = "jobs"
+	defaultTick = 30 * time.Second
+	// a failed job is retried after retryDelay, twice as long after every next failure, up to maxAttempts times
+	retryDelay  = time.Minute
+	maxAttempts = 5
+)
+
+// Job is a persistent task which runs once at the given time.
+type Job struct {
+	ID      string          `json:"id"`
+	Kind    string          `json:"kind"`
+	At      time.Time       `json:"at"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+	// Attempts is how many times the job has failed.
+	Attempts int `json:"attempts,omitempty"`
+}
+
+// Handler runs jobs of a kind.
+type Handler func(job Job) error
+
+type task struct {
+	every time.Duration
+	next  time.Time
+	fn    func(now time.Time)
+}
+
+// Scheduler runs persistent jobs at their time and periodic tasks at their interval.
+// Jobs survive restarts, periodic tasks have to be registered on every start.
+type Scheduler struct {
+	mu       sync.Mutex
+	store    store.Store
+	handlers map[string]Handler
+	tasks    []*task
+	seq      int
+}
+
+// New returns Scheduler which keeps jobs in s.
+func New(s store.Store) *Scheduler {
+	return &Scheduler{store: s, handlers: make(map[string]Handler)}
+}
+
+// Handle registers handler for jobs of kind.
+func (s *Scheduler) Handle(kind string, h Handler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.handlers[kind] = h
+}
+
+// Every registers fn to be called every d, the first call happens on the first tick.
+func (s *Scheduler) Every(d time.Duration, fn func(now time.Time)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.tasks = append(s.tasks, &task{every: d, fn: fn})
+}
+
+// At schedules job of kind at time at and returns its id. Payload is encoded to JSON.
+func (s *Scheduler) At(kind string, at time.Time, payload interface{}) (string, error) {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("error when marshal payload of %s job: %v", kind, err)
+	}
+
+	s.mu.Lock()
+	s.seq++
+	id := kind + "-" + strconv.FormatInt(time.Now().UnixNano(), 36) + strconv.Itoa(s.seq)
+	s.mu.Unlock()
+
+	job := Job{ID: id, Kind: kind, At: at, Payload: raw}
+	if err = s.store.Put(bucket, id, job); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// Cancel removes job by id.
+func (s *Scheduler) Cancel(id string) error {
+	return s.store.Delete(bucket, id)
+}
+
+// Pending returns jobs of kind which have not run yet.
+func (s *Scheduler) Pending(kind string) ([]Job, error) {
+	keys, err := s.store.Keys(bucket)
+	if err != nil {
+		return nil, err
+	}
+	var jobs []Job
+	for _, k := range keys {
+		var job Job
+		if _, err := s.store.Get(bucket, k, &job); err != nil {
+			return nil, err
+		}
+		if job.Kind == kind {
+			jobs = append(jobs, job)
+		}
+	}
+	return jobs, nil
+}
+
+// Run calls Tick every tick until stop is closed.
+func (s *Scheduler) Run(tick time.Duration, stop <-chan struct{}) {
+	if tick <= 0 {
+		tick = defaultTick
+	}
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+
+	s.Tick(time.Now())
+	for {
+		select {
+		case now := <-ticker.C:
+			s.Tick(now)
+		case <-stop:
+			return
+		}
+	}
+}
+
+// Tick runs due periodic tasks and jobs. A job is removed once its handler has succeeded, a failed job
+// is retried later and dropped after maxAttempts. Jobs without a handler are kept until one is registered.
+func (s *Scheduler) Tick(now time.Time) {
+	s.mu.Lock()
+	var due []*task
+	for _, t := range s.tasks {
+		if !t.next.After(now) {
+			t.next = now.Add(t.every)
+			due = append(due, t)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, t := range due {
+		fn := t.fn
+		if err := protect(func() error { fn(now); return nil }); err != nil {
+			log.Printf("periodic task failed: %v", err)
+		}
+	}
+
+	keys, err := s.store.Keys(bucket)
+	if err != nil {
+		log.Print(err)
+		return
+	}
+	for _, k := range keys {
+		var job Job
+		if ok, err := s.store.Get(bucket, k, &job); err != nil || !ok {
+			continue
+		}
+		if job.At.After(now) {
+			continue
+		}
+
+		s.mu.Lock()
+		h, ok := s.handlers[job.Kind]
+		s.mu.Unlock()
+		if !ok {
+			continue
+		}
+
+		if err = protect(func() error { return h(job) }); err == nil {
+			if err = s.store.Delete(bucket, k); err != nil {
+				log.Print(err)
+			}
+			continue
+		}
+
+		job.Attempts++
+		if job.Attempts >= maxAttempts {
+			log.Printf("job %s failed %d times, dropped: %v", job.ID, job.Attempts, err)
+			if err = s.store.Delete(bucket, k); err != nil {
+				log.Print(err)
+			}
+			continue
+		}
+		job.At = now.Add(retryDelay << (job.Attempts - 1))
+		log.Printf("job %s failed, retrying at %s: %v", job.ID, job.At.Format(time.RFC3339), err)
+		if err = s.store.Put(bucket, k, job); err != nil {
+			log.Print(err)
+		}
+	}
+}
+
+// protect calls fn and turns its panic into error, so one failing task doesn't stop the others.
+func protect(fn func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return fn()
+}
+
+// Decode decodes job payload into v.
+func (j Job) Decode(v interface{}) error {
+	if err := json.Unmarshal(j.Payload, v); err != nil {
+		return fmt.Errorf("error when unmarshal payload of job %s: %v", j.ID, err)
+	}
+	return nil
+}
+
+// NextWeekly returns the first time after now which falls on weekday at hour:00 UTC.
+func NextWeekly(now time.Time, weekday time.Weekday, hour int) time.Time {
+	now = now.UTC()
+	next := time.Date(now.Year(), now.Month(), now.Day(), hour, 0, 0, 0, time.UTC)
+	next = next.AddDate(0, 0, (int(weekday)-int(now.Weekday())+7)%7)
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 7)
+	}
+	return next
+}
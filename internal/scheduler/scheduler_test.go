@@ -0,0 +1,135 @@
+package scheduler
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alebsys/telegram-article-bot/internal/store"
+)
+
+func TestTick(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	s := New(store.NewMemory())
+
+	var ran []string
+	s.Handle("say", func(job Job) error {
+		var word string
+		if err := job.Decode(&word); err != nil {
+			return err
+		}
+		ran = append(ran, word)
+		return nil
+	})
+	ticks := 0
+	s.Every(time.Minute, func(time.Time) { ticks++ })
+
+	s.At("say", now.Add(-time.Minute), "past")
+	s.At("say", now.Add(time.Hour), "future")
+	cancelled, _ := s.At("say", now, "cancelled")
+	s.At("unknown", now, "kept")
+	s.Cancel(cancelled)
+
+	if jobs, _ := s.Pending("say"); len(jobs) != 2 {
+		t.Errorf("Pending: got %d jobs; want 2", len(jobs))
+	}
+
+	s.Tick(now)
+	s.Tick(now.Add(30 * time.Second))
+	s.Tick(now.Add(2 * time.Hour))
+
+	want := []string{"past", "future"}
+	if len(ran) != len(want) {
+		t.Fatalf("Tick: got %v; want %v", ran, want)
+	}
+	for i := range want {
+		if ran[i] != want[i] {
+			t.Errorf("Tick: got %v; want %v", ran, want)
+		}
+	}
+	if ticks != 2 {
+		t.Errorf("Tick: periodic task ran %d times; want 2", ticks)
+	}
+	if keys, _ := s.store.Keys(bucket); len(keys) != 1 {
+		t.Errorf("Tick: jobs left %v; want only unknown job", keys)
+	}
+}
+
+func TestTickPanic(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	s := New(store.NewMemory())
+
+	ran := false
+	s.Every(time.Minute, func(time.Time) { panic("broken task") })
+	s.Handle("broken", func(Job) error { panic("broken job") })
+	s.Handle("ok", func(Job) error { ran = true; return nil })
+	s.At("broken", now, nil)
+	s.At("ok", now, nil)
+
+	s.Tick(now)
+	if !ran {
+		t.Errorf("Tick: panicking task stopped other jobs")
+	}
+	if jobs, _ := s.Pending("broken"); len(jobs) != 1 || jobs[0].Attempts != 1 || !jobs[0].At.Equal(now.Add(retryDelay)) {
+		t.Errorf("Tick: got %+v; want the failed job rescheduled", jobs)
+	}
+	if jobs, _ := s.Pending("ok"); len(jobs) != 0 {
+		t.Errorf("Tick: got %+v; want the succeeded job removed", jobs)
+	}
+}
+
+func TestTickRetry(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	s := New(store.NewMemory())
+
+	calls := 0
+	s.Handle("flaky", func(Job) error {
+		calls++
+		if calls < 3 {
+			return errors.New("temporary failure")
+		}
+		return nil
+	})
+	s.At("flaky", now, nil)
+
+	for i := 0; i < 10; i++ {
+		s.Tick(now.Add(time.Duration(i) * time.Minute))
+	}
+	if calls != 3 {
+		t.Errorf("Tick: handler called %d times; want 3, retries after 1 and 2 minutes", calls)
+	}
+	if jobs, _ := s.Pending("flaky"); len(jobs) != 0 {
+		t.Errorf("Tick: got %+v; want the job removed after success", jobs)
+	}
+
+	s.Handle("broken", func(Job) error { return errors.New("permanent failure") })
+	s.At("broken", now, nil)
+	for i := 0; i < 100; i++ {
+		s.Tick(now.Add(time.Duration(i) * time.Minute))
+	}
+	if jobs, _ := s.Pending("broken"); len(jobs) != 0 {
+		t.Errorf("Tick: got %+v; want the job dropped after %d attempts", jobs, maxAttempts)
+	}
+}
+
+func TestNextWeekly(t *testing.T) {
+	// 2026-10-16 is Friday
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	cases := []struct {
+		name    string
+		weekday time.Weekday
+		hour    int
+		want    time.Time
+	}{
+		{"later today", time.Friday, 15, time.Date(2026, 10, 16, 15, 0, 0, 0, time.UTC)},
+		{"earlier today", time.Friday, 9, time.Date(2026, 10, 23, 9, 0, 0, 0, time.UTC)},
+		{"this hour", time.Friday, 12, time.Date(2026, 10, 23, 12, 0, 0, 0, time.UTC)},
+		{"next monday", time.Monday, 10, time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)},
+	}
+	for _, c := range cases {
+		got := NextWeekly(now, c.weekday, c.hour)
+		if !got.Equal(c.want) {
+			t.Errorf("NextWeekly: %s; got %v; want %v", c.name, got, c.want)
+		}
+	}
+}
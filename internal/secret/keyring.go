@@ -0,0 +1,85 @@
+package secret
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// EnvKeyID is the id of the key set with an environment variable.
+const EnvKeyID = "env"
+
+// Key is a master key which encrypts data keys of secrets.
+type Key struct {
+	ID    string
+	Bytes []byte
+}
+
+// Keyring is a set of master keys. The primary key encrypts new secrets, the others
+// only decrypt secrets encrypted before rotation.
+type Keyring struct {
+	primary string
+	boxes   map[string]*Box
+}
+
+// NewKeyring returns Keyring of keys, the first key is the primary one.
+func NewKeyring(keys ...Key) (*Keyring, error) {
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no keys")
+	}
+	k := &Keyring{primary: keys[0].ID, boxes: make(map[string]*Box)}
+	for _, key := range keys {
+		if key.ID == "" {
+			return nil, fmt.Errorf("key without id")
+		}
+		if _, ok := k.boxes[key.ID]; ok {
+			return nil, fmt.Errorf("duplicate key %s", key.ID)
+		}
+		box, err := NewBox(key.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("error when makes key %s: %v", key.ID, err)
+		}
+		k.boxes[key.ID] = box
+	}
+	return k, nil
+}
+
+// ParseKeyFile reads keys from lines "<id> <base64 key>". Empty lines and lines starting with '#' are skipped.
+// Values of keys never get into errors.
+func ParseKeyFile(r io.Reader) ([]Key, error) {
+	var keys []Key
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("line %d: want '<id> <base64 key>'", n)
+		}
+		key, err := ParseKey(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: key %s is invalid", n, fields[0])
+		}
+		keys = append(keys, Key{ID: fields[0], Bytes: key})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error when reads key file: %v", err)
+	}
+	return keys, nil
+}
+
+// Primary returns id of the primary key.
+func (k *Keyring) Primary() string {
+	return k.primary
+}
+
+func (k *Keyring) box(id string) (*Box, error) {
+	box, ok := k.boxes[id]
+	if !ok {
+		return nil, fmt.Errorf("unknown key %s", id)
+	}
+	return box, nil
+}
@@ -0,0 +1,142 @@
+package secret
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/alebsys/telegram-article-bot/internal/store"
+)
+
+func TestBox(t *testing.T) {
+	box, err := NewBox(bytes.Repeat([]byte{1}, KeySize))
+	if err != nil {
+		t.Fatalf("NewBox: got error %v", err)
+	}
+
+	sealed, err := box.Seal([]byte("api key"))
+	if err != nil {
+		t.Fatalf("Seal: got error %v", err)
+	}
+	if strings.Contains(sealed, "api key") {
+		t.Errorf("Seal: got plaintext in %q", sealed)
+	}
+	plaintext, err := box.Open(sealed)
+	if err != nil || string(plaintext) != "api key" {
+		t.Errorf("Open: got %q, %v; want %q", plaintext, err, "api key")
+	}
+
+	other, _ := NewBox(bytes.Repeat([]byte{2}, KeySize))
+	if _, err = other.Open(sealed); err == nil {
+		t.Errorf("Open: wrong key; got no error")
+	}
+}
+
+func TestParseKey(t *testing.T) {
+	if _, err := ParseKey(base64.StdEncoding.EncodeToString(make([]byte, KeySize))); err != nil {
+		t.Errorf("ParseKey: got error %v", err)
+	}
+	if _, err := ParseKey(base64.StdEncoding.EncodeToString(make([]byte, 16))); err == nil {
+		t.Errorf("ParseKey: short key; got no error")
+	}
+	if _, err := ParseKey("not base64!"); err == nil {
+		t.Errorf("ParseKey: bad encoding; got no error")
+	}
+}
+
+func TestVault(t *testing.T) {
+	oldKey := Key{ID: "old", Bytes: bytes.Repeat([]byte{1}, KeySize)}
+	newKey := Key{ID: "new", Bytes: bytes.Repeat([]byte{2}, KeySize)}
+	s := store.NewMemory()
+
+	keys, _ := NewKeyring(oldKey)
+	v := NewVault(s, keys)
+	if err := v.Put("devto:1", NewValue("s3cr3t")); err != nil {
+		t.Fatalf("Put: got error %v", err)
+	}
+	var env envelope
+	s.Get(bucket, "devto:1", &env)
+	if env.KeyID != "old" || strings.Contains(env.Value, "s3cr3t") {
+		t.Errorf("Put: got envelope %+v", env)
+	}
+
+	keys, _ = NewKeyring(newKey, oldKey)
+	v = NewVault(s, keys)
+	if n, err := v.Rotate(); err != nil || n != 1 {
+		t.Fatalf("Rotate: got %d, %v; want 1 secret", n, err)
+	}
+	if n, _ := v.Rotate(); n != 0 {
+		t.Errorf("Rotate: again; got %d secrets; want 0", n)
+	}
+
+	// the old key is not needed after rotation
+	keys, _ = NewKeyring(newKey)
+	v = NewVault(s, keys)
+	got, ok, err := v.Get("devto:1")
+	if err != nil || !ok || got.Reveal() != "s3cr3t" {
+		t.Errorf("Get: got %q, %v, %v; want s3cr3t", got.Reveal(), ok, err)
+	}
+
+	if err = v.Delete("devto:1"); err != nil {
+		t.Fatalf("Delete: got error %v", err)
+	}
+	if _, ok, _ = v.Get("devto:1"); ok {
+		t.Errorf("Get: after Delete; got secret")
+	}
+}
+
+func TestWithoutSecrets(t *testing.T) {
+	s := store.NewMemory()
+	keys, _ := NewKeyring(Key{ID: "k", Bytes: bytes.Repeat([]byte{1}, KeySize)})
+	NewVault(s, keys).Put("devto:1", NewValue("s3cr3t"))
+
+	hidden := WithoutSecrets(s)
+	var env envelope
+	if _, err := hidden.Get(bucket, "devto:1", &env); err == nil {
+		t.Errorf("Get: got no error for the bucket of the vault")
+	}
+	if _, err := hidden.Keys(bucket); err == nil {
+		t.Errorf("Keys: got no error for the bucket of the vault")
+	}
+	if err := hidden.Put(bucket, "devto:1", env); err == nil {
+		t.Errorf("Put: got no error for the bucket of the vault")
+	}
+	batch := &store.Batch{}
+	batch.Put("accounts", "1", 1)
+	batch.Delete(bucket, "devto:1")
+	if err := store.Apply(hidden, batch); err == nil {
+		t.Errorf("Apply: got no error for a batch changing the bucket of the vault")
+	}
+	if err := hidden.Put("accounts", "1", 1); err != nil {
+		t.Errorf("Put: got error %v for another bucket", err)
+	}
+}
+
+func TestValue(t *testing.T) {
+	v := NewValue("s3cr3t")
+	data, _ := json.Marshal(struct{ Key Value }{v})
+	for _, s := range []string{fmt.Sprint(v), fmt.Sprintf("%+v %#v", v, v), string(data)} {
+		if strings.Contains(s, "s3cr3t") {
+			t.Errorf("Value: got secret in %q", s)
+		}
+	}
+}
+
+func TestParseKeyFile(t *testing.T) {
+	key := base64.StdEncoding.EncodeToString(make([]byte, KeySize))
+	keys, err := ParseKeyFile(strings.NewReader("# rotated in October\n2026-10 " + key + "\n\nenv " + key + "\n"))
+	if err != nil {
+		t.Fatalf("ParseKeyFile: got error %v", err)
+	}
+	if len(keys) != 2 || keys[0].ID != "2026-10" || keys[1].ID != "env" {
+		t.Errorf("ParseKeyFile: got %d keys", len(keys))
+	}
+
+	_, err = ParseKeyFile(strings.NewReader("bad c2VjcmV0"))
+	if err == nil || strings.Contains(err.Error(), "c2VjcmV0") {
+		t.Errorf("ParseKeyFile: bad key; got error %v", err)
+	}
+}
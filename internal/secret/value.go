@@ -0,0 +1,36 @@
+package secret
+
+const redacted = "[redacted]"
+
+// Value is a secret which is not printed by fmt and log and not marshaled to JSON.
+// Use Reveal to get the secret itself.
+type Value struct {
+	s string
+}
+
+// NewValue returns Value of s.
+func NewValue(s string) Value {
+	return Value{s: s}
+}
+
+// Reveal returns the secret.
+func (v Value) Reveal() string {
+	return v.s
+}
+
+// IsZero reports whether the secret is empty.
+func (v Value) IsZero() bool {
+	return v.s == ""
+}
+
+func (v Value) String() string {
+	return redacted
+}
+
+func (v Value) GoString() string {
+	return redacted
+}
+
+func (v Value) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + redacted + `"`), nil
+}
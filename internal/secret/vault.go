@@ -0,0 +1,198 @@
+package secret
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/alebsys/telegram-article-bot/internal/store"
+)
+
+const bucket = "secrets"
+
+// envelope is a secret encrypted with its own data key, the data key is encrypted with a master key.
+type envelope struct {
+	KeyID   string `json:"key_id"`
+	DataKey string `json:"data_key"`
+	Value   string `json:"value"`
+}
+
+// Vault keeps secrets in store with envelope encryption. It is the only way to store secrets:
+// other storages keep names of secrets and never their values.
+type Vault struct {
+	mu    sync.Mutex
+	store store.Store
+	keys  *Keyring
+}
+
+// NewVault returns Vault backed by s which encrypts with keys.
+func NewVault(s store.Store, keys *Keyring) *Vault {
+	return &Vault{store: s, keys: keys}
+}
+
+// Put encrypts secret with a new data key and saves it by name.
+func (v *Vault) Put(name string, secret Value) error {
+	dataKey := make([]byte, KeySize)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return fmt.Errorf("error when makes data key: %v", err)
+	}
+	box, err := NewBox(dataKey)
+	if err != nil {
+		return err
+	}
+	value, err := box.Seal([]byte(secret.Reveal()))
+	if err != nil {
+		return err
+	}
+	env := envelope{Value: value}
+	if err = v.wrap(&env, dataKey); err != nil {
+		return err
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if err = v.store.Put(bucket, name, env); err != nil {
+		return fmt.Errorf("error when saves secret %s: %v", name, err)
+	}
+	return nil
+}
+
+// Get decrypts secret by name.
+func (v *Vault) Get(name string) (Value, bool, error) {
+	var env envelope
+	ok, err := v.store.Get(bucket, name, &env)
+	if err != nil || !ok {
+		return Value{}, false, err
+	}
+	dataKey, err := v.unwrap(env)
+	if err != nil {
+		return Value{}, false, fmt.Errorf("error when decrypts secret %s: %v", name, err)
+	}
+	box, err := NewBox(dataKey)
+	if err != nil {
+		return Value{}, false, err
+	}
+	plaintext, err := box.Open(env.Value)
+	if err != nil {
+		return Value{}, false, fmt.Errorf("error when decrypts secret %s: %v", name, err)
+	}
+	return NewValue(string(plaintext)), true, nil
+}
+
+// Delete removes secret by name.
+func (v *Vault) Delete(name string) error {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	return v.store.Delete(bucket, name)
+}
+
+// Rotate encrypts data keys of secrets encrypted with old master keys with the primary one
+// and returns number of re-encrypted secrets. Old keys can be removed after rotation.
+func (v *Vault) Rotate() (int, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	names, err := v.store.Keys(bucket)
+	if err != nil {
+		return 0, err
+	}
+	var n int
+	for _, name := range names {
+		var env envelope
+		if _, err = v.store.Get(bucket, name, &env); err != nil {
+			return n, err
+		}
+		if env.KeyID == v.keys.Primary() {
+			continue
+		}
+		dataKey, err := v.unwrap(env)
+		if err != nil {
+			return n, fmt.Errorf("error when decrypts secret %s: %v", name, err)
+		}
+		if err = v.wrap(&env, dataKey); err != nil {
+			return n, err
+		}
+		if err = v.store.Put(bucket, name, env); err != nil {
+			return n, fmt.Errorf("error when saves secret %s: %v", name, err)
+		}
+		n++
+	}
+	return n, nil
+}
+
+func (v *Vault) wrap(env *envelope, dataKey []byte) error {
+	box, err := v.keys.box(v.keys.Primary())
+	if err != nil {
+		return err
+	}
+	wrapped, err := box.Seal(dataKey)
+	if err != nil {
+		return err
+	}
+	env.KeyID, env.DataKey = v.keys.Primary(), wrapped
+	return nil
+}
+
+func (v *Vault) unwrap(env envelope) ([]byte, error) {
+	box, err := v.keys.box(env.KeyID)
+	if err != nil {
+		return nil, err
+	}
+	return box.Open(env.DataKey)
+}
+
+// errHidden is returned by a store from WithoutSecrets on access to the bucket of the vault.
+var errHidden = fmt.Errorf("bucket %s is accessible only through the vault", bucket)
+
+// hiddenStore is a Store which refuses access to the bucket of the vault.
+type hiddenStore struct {
+	store store.Store
+}
+
+// WithoutSecrets returns s which refuses access to the bucket of the vault,
+// so everything except the vault gets secrets only through it.
+func WithoutSecrets(s store.Store) store.Store {
+	return &hiddenStore{store: s}
+}
+
+// Get decodes the value of key in bucket into v and reports whether the key exists.
+func (h *hiddenStore) Get(b, key string, v interface{}) (bool, error) {
+	if b == bucket {
+		return false, errHidden
+	}
+	return h.store.Get(b, key, v)
+}
+
+// Put encodes v and saves it under key in bucket.
+func (h *hiddenStore) Put(b, key string, v interface{}) error {
+	if b == bucket {
+		return errHidden
+	}
+	return h.store.Put(b, key, v)
+}
+
+// Delete removes key from bucket.
+func (h *hiddenStore) Delete(b, key string) error {
+	if b == bucket {
+		return errHidden
+	}
+	return h.store.Delete(b, key)
+}
+
+// Keys returns the sorted keys of bucket.
+func (h *hiddenStore) Keys(b string) ([]string, error) {
+	if b == bucket {
+		return nil, errHidden
+	}
+	return h.store.Keys(b)
+}
+
+// Apply makes changes of batch at once unless it touches the bucket of the vault.
+func (h *hiddenStore) Apply(batch *store.Batch) error {
+	if batch.Has(bucket) {
+		return errHidden
+	}
+	return store.Apply(h.store, batch)
+}
@@ -0,0 +1,150 @@
+package settings
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/alebsys/telegram-article-bot/internal/store"
+)
+
+const bucket = "settings"
+
+// Setting describes a chat setting. Empty Values allows any value accepted by Validate.
+type Setting struct {
+	Name        string
+	Description string
+	Default     string
+	Values      []string
+	Validate    func(value string) error
+}
+
+// Settings keeps settings of chats.
+type Settings struct {
+	mu    sync.Mutex
+	store store.Store
+	known map[string]Setting
+}
+
+// New returns Settings backed by s which accepts only known settings.
+func New(s store.Store, known ...Setting) *Settings {
+	set := &Settings{store: s, known: make(map[string]Setting)}
+	for _, k := range known {
+		set.known[k.Name] = k
+	}
+	return set
+}
+
+// Known returns descriptions of all settings sorted by name.
+func (s *Settings) Known() []Setting {
+	known := make([]Setting, 0, len(s.known))
+	for _, k := range s.known {
+		known = append(known, k)
+	}
+	sort.Slice(known, func(i, j int) bool {
+		return known[i].Name < known[j].Name
+	})
+	return known
+}
+
+// Get returns value of setting in chat or its default value.
+func (s *Settings) Get(chatID int64, name string) (string, error) {
+	k, ok := s.known[name]
+	if !ok {
+		return "", fmt.Errorf("unknown setting %q", name)
+	}
+	values, err := s.values(chatID)
+	if err != nil {
+		return "", err
+	}
+	if v, ok := values[name]; ok {
+		return v, nil
+	}
+	return k.Default, nil
+}
+
+// Set validates and saves value of setting in chat. Setting to the default value removes it.
+func (s *Settings) Set(chatID int64, name, value string) error {
+	k, ok := s.known[name]
+	if !ok {
+		return fmt.Errorf("unknown setting %q", name)
+	}
+	if err := k.validate(value); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	values, err := s.values(chatID)
+	if err != nil {
+		return err
+	}
+	if value == k.Default {
+		delete(values, name)
+	} else {
+		values[name] = value
+	}
+	if len(values) == 0 {
+		return s.store.Delete(bucket, key(chatID))
+	}
+	return s.store.Put(bucket, key(chatID), values)
+}
+
+// All returns values of all known settings in chat.
+func (s *Settings) All(chatID int64) (map[string]string, error) {
+	values, err := s.values(chatID)
+	if err != nil {
+		return nil, err
+	}
+	all := make(map[string]string, len(s.known))
+	for name, k := range s.known {
+		all[name] = k.Default
+		if v, ok := values[name]; ok {
+			all[name] = v
+		}
+	}
+	return all, nil
+}
+
+// Export returns settings of user private chat.
+func (s *Settings) Export(userID int64) (interface{}, error) {
+	return s.values(userID)
+}
+
+// Forget removes settings of user private chat.
+func (s *Settings) Forget(userID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.store.Delete(bucket, key(userID))
+}
+
+func (s *Settings) values(chatID int64) (map[string]string, error) {
+	values := make(map[string]string)
+	if _, err := s.store.Get(bucket, key(chatID), &values); err != nil {
+		return nil, fmt.Errorf("error when reads settings of %d: %v", chatID, err)
+	}
+	return values, nil
+}
+
+func (k Setting) validate(value string) error {
+	if len(k.Values) > 0 {
+		for _, v := range k.Values {
+			if v == value {
+				return nil
+			}
+		}
+		return fmt.Errorf("%s must be one of: %s", k.Name, strings.Join(k.Values, ", "))
+	}
+	if k.Validate != nil {
+		return k.Validate(value)
+	}
+	return nil
+}
+
+func key(chatID int64) string {
+	return strconv.FormatInt(chatID, 10)
+}
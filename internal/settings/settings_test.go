@@ -0,0 +1,53 @@
+package settings
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/alebsys/telegram-article-bot/internal/store"
+)
+
+func TestSettings(t *testing.T) {
+	s := New(store.NewMemory(),
+		Setting{Name: "mode", Default: "off", Values: []string{"off", "on"}},
+		Setting{Name: "hour", Default: "9", Validate: func(v string) error {
+			if len(v) > 2 {
+				return fmt.Errorf("bad hour")
+			}
+			return nil
+		}},
+	)
+
+	cases := []struct {
+		name    string
+		setting string
+		value   string
+		failed  bool
+		want    string
+	}{
+		{"allowed value", "mode", "on", false, "on"},
+		{"not allowed value", "mode", "maybe", true, "on"},
+		{"validated value", "hour", "10", false, "10"},
+		{"not validated value", "hour", "100", true, "10"},
+		{"back to default", "hour", "9", false, "9"},
+		{"unknown setting", "color", "red", true, ""},
+	}
+	for _, c := range cases {
+		err := s.Set(1, c.setting, c.value)
+		if (err != nil) != c.failed {
+			t.Errorf("Set: %s; got error %v; want error %v", c.name, err, c.failed)
+		}
+		got, _ := s.Get(1, c.setting)
+		if got != c.want {
+			t.Errorf("Get: %s; got %q; want %q", c.name, got, c.want)
+		}
+	}
+
+	values, _ := s.values(1)
+	if len(values) != 1 {
+		t.Errorf("Set: default values are stored, got %v", values)
+	}
+	if v, _ := s.Get(2, "mode"); v != "off" {
+		t.Errorf("Get: other chat; got %q; want default", v)
+	}
+}
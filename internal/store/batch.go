@@ -0,0 +1,67 @@
+package store
+
+// Batch is a list of changes which Apply makes to a Store at once.
+type Batch struct {
+	ops []op
+}
+
+type op struct {
+	bucket string
+	key    string
+	value  interface{}
+	delete bool
+}
+
+// Put adds saving v under key in bucket to the batch.
+func (b *Batch) Put(bucket, key string, v interface{}) {
+	b.ops = append(b.ops, op{bucket: bucket, key: key, value: v})
+}
+
+// Delete adds removal of key from bucket to the batch.
+func (b *Batch) Delete(bucket, key string) {
+	b.ops = append(b.ops, op{bucket: bucket, key: key, delete: true})
+}
+
+// Len returns the number of changes in the batch.
+func (b *Batch) Len() int {
+	return len(b.ops)
+}
+
+// Has reports whether the batch changes bucket.
+func (b *Batch) Has(bucket string) bool {
+	for _, o := range b.ops {
+		if o.bucket == bucket {
+			return true
+		}
+	}
+	return false
+}
+
+// Batcher is a Store which makes all changes of a batch at once, e.g. writing its file once instead of on every change.
+type Batcher interface {
+	Store
+	// Apply makes changes of b in order.
+	Apply(b *Batch) error
+}
+
+// Apply makes changes of b to s at once if s is a Batcher or one by one otherwise.
+func Apply(s Store, b *Batch) error {
+	if b.Len() == 0 {
+		return nil
+	}
+	if bs, ok := s.(Batcher); ok {
+		return bs.Apply(b)
+	}
+	for _, o := range b.ops {
+		var err error
+		if o.delete {
+			err = s.Delete(o.bucket, o.key)
+		} else {
+			err = s.Put(o.bucket, o.key, o.value)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package store
+
+// Prefixed is a Store which keeps its buckets in another Store under names starting with a prefix,
+// so several owners can share one Store without seeing each other's data.
+type Prefixed struct {
+	store  Store
+	prefix string
+}
+
+// WithPrefix returns s with bucket names prefixed by prefix and a slash. Empty prefix returns s itself.
+func WithPrefix(s Store, prefix string) Store {
+	if prefix == "" {
+		return s
+	}
+	return &Prefixed{store: s, prefix: prefix + "/"}
+}
+
+// Get decodes the value of key in bucket into v and reports whether the key exists.
+func (p *Prefixed) Get(bucket, key string, v interface{}) (bool, error) {
+	return p.store.Get(p.prefix+bucket, key, v)
+}
+
+// Put encodes v and saves it under key in bucket.
+func (p *Prefixed) Put(bucket, key string, v interface{}) error {
+	return p.store.Put(p.prefix+bucket, key, v)
+}
+
+// Delete removes key from bucket.
+func (p *Prefixed) Delete(bucket, key string) error {
+	return p.store.Delete(p.prefix+bucket, key)
+}
+
+// Keys returns the sorted keys of bucket.
+func (p *Prefixed) Keys(bucket string) ([]string, error) {
+	return p.store.Keys(p.prefix + bucket)
+}
+
+// Apply makes changes of b in buckets with the prefix at once if the underlying Store can.
+func (p *Prefixed) Apply(b *Batch) error {
+	prefixed := &Batch{ops: make([]op, len(b.ops))}
+	for i, o := range b.ops {
+		o.bucket = p.prefix + o.bucket
+		prefixed.ops[i] = o
+	}
+	return Apply(p.store, prefixed)
+}
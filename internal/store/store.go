@@ -0,0 +1,231 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// Store is a key-value storage where values are grouped into buckets.
+type Store interface {
+	// Get decodes the value of key in bucket into v and reports whether the key exists.
+	Get(bucket, key string, v interface{}) (bool, error)
+	// Put encodes v and saves it under key in bucket.
+	Put(bucket, key string, v interface{}) error
+	// Delete removes key from bucket.
+	Delete(bucket, key string) error
+	// Keys returns the sorted keys of bucket.
+	Keys(bucket string) ([]string, error)
+}
+
+// Snapshotter is a Store which can be copied as a whole, e.g. to move data to another host or backend.
+type Snapshotter interface {
+	Store
+	// Snapshot returns all values by bucket and key.
+	Snapshot() (map[string]map[string]json.RawMessage, error)
+	// Restore replaces all values with those of data.
+	Restore(data map[string]map[string]json.RawMessage) error
+}
+
+// JSONStore keeps all buckets in memory and, if path is set, dumps them to a JSON file on every change.
+type JSONStore struct {
+	mu      sync.RWMutex
+	path    string
+	buckets map[string]map[string]json.RawMessage
+}
+
+// NewMemory returns a JSONStore which is never written to disk.
+func NewMemory() *JSONStore {
+	return &JSONStore{buckets: make(map[string]map[string]json.RawMessage)}
+}
+
+// Open loads JSONStore from file at path. Missing file means empty store, empty path means memory only store.
+func Open(path string) (*JSONStore, error) {
+	s := NewMemory()
+	s.path = path
+	if path == "" {
+		return s, nil
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error when reads store file %s: %v", path, err)
+	}
+	if err = json.Unmarshal(data, &s.buckets); err != nil {
+		return nil, fmt.Errorf("error when unmarshal store file %s: %v", path, err)
+	}
+	if s.buckets == nil {
+		s.buckets = make(map[string]map[string]json.RawMessage)
+	}
+	return s, nil
+}
+
+// Get decodes the value of key in bucket into v and reports whether the key exists.
+func (s *JSONStore) Get(bucket, key string, v interface{}) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	raw, ok := s.buckets[bucket][key]
+	if !ok {
+		return false, nil
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return true, fmt.Errorf("error when unmarshal %s/%s: %v", bucket, key, err)
+	}
+	return true, nil
+}
+
+// Put encodes v and saves it under key in bucket.
+func (s *JSONStore) Put(bucket, key string, v interface{}) error {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("error when marshal %s/%s: %v", bucket, key, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.buckets[bucket] == nil {
+		s.buckets[bucket] = make(map[string]json.RawMessage)
+	}
+	s.buckets[bucket][key] = raw
+	return s.save()
+}
+
+// Delete removes key from bucket.
+func (s *JSONStore) Delete(bucket, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.buckets[bucket][key]; !ok {
+		return nil
+	}
+	delete(s.buckets[bucket], key)
+	if len(s.buckets[bucket]) == 0 {
+		delete(s.buckets, bucket)
+	}
+	return s.save()
+}
+
+// Apply makes changes of b in order and writes the store file once.
+// Nothing is changed if a value of b can't be encoded.
+func (s *JSONStore) Apply(b *Batch) error {
+	raws := make([]json.RawMessage, len(b.ops))
+	for i, o := range b.ops {
+		if o.delete {
+			continue
+		}
+		raw, err := json.Marshal(o.value)
+		if err != nil {
+			return fmt.Errorf("error when marshal %s/%s: %v", o.bucket, o.key, err)
+		}
+		raws[i] = raw
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, o := range b.ops {
+		if o.delete {
+			delete(s.buckets[o.bucket], o.key)
+			if len(s.buckets[o.bucket]) == 0 {
+				delete(s.buckets, o.bucket)
+			}
+			continue
+		}
+		if s.buckets[o.bucket] == nil {
+			s.buckets[o.bucket] = make(map[string]json.RawMessage)
+		}
+		s.buckets[o.bucket][o.key] = raws[i]
+	}
+	return s.save()
+}
+
+// Keys returns the sorted keys of bucket.
+func (s *JSONStore) Keys(bucket string) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	keys := make([]string, 0, len(s.buckets[bucket]))
+	for k := range s.buckets[bucket] {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// Snapshot returns all values by bucket and key.
+func (s *JSONStore) Snapshot() (map[string]map[string]json.RawMessage, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	data := make(map[string]map[string]json.RawMessage, len(s.buckets))
+	for name, b := range s.buckets {
+		data[name] = make(map[string]json.RawMessage, len(b))
+		for k, v := range b {
+			data[name][k] = append(json.RawMessage(nil), v...)
+		}
+	}
+	return data, nil
+}
+
+// Restore replaces all values with those of data and writes the store file once.
+func (s *JSONStore) Restore(data map[string]map[string]json.RawMessage) error {
+	buckets := make(map[string]map[string]json.RawMessage, len(data))
+	for name, b := range data {
+		if len(b) == 0 {
+			continue
+		}
+		buckets[name] = make(map[string]json.RawMessage, len(b))
+		for k, v := range b {
+			if !json.Valid(v) {
+				return fmt.Errorf("error when restores %s/%s: invalid JSON", name, k)
+			}
+			buckets[name][k] = append(json.RawMessage(nil), v...)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.buckets = buckets
+	return s.save()
+}
+
+// save writes all buckets to a temporary file and renames it over the store file.
+// Caller must hold the write lock.
+func (s *JSONStore) save() error {
+	if s.path == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(s.buckets)
+	if err != nil {
+		return fmt.Errorf("error when marshal store: %v", err)
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("error when creates temporary store file: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err = tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("error when writes store file: %v", err)
+	}
+	if err = tmp.Close(); err != nil {
+		return fmt.Errorf("error when closes store file: %v", err)
+	}
+	if err = os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("error when renames store file: %v", err)
+	}
+	return nil
+}
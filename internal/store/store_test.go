@@ -0,0 +1,159 @@
+package store
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestJSONStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store.json")
+
+	s, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open: got error %v", err)
+	}
+	if err = s.Put("bucket", "b", []int{1, 2}); err != nil {
+		t.Fatalf("Put: got error %v", err)
+	}
+	if err = s.Put("bucket", "a", []int{3}); err != nil {
+		t.Fatalf("Put: got error %v", err)
+	}
+	if err = s.Delete("bucket", "a"); err != nil {
+		t.Fatalf("Delete: got error %v", err)
+	}
+
+	reopened, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open: got error %v", err)
+	}
+
+	cases := []struct {
+		name  string
+		key   string
+		want  []int
+		found bool
+	}{
+		{"saved key", "b", []int{1, 2}, true},
+		{"deleted key", "a", nil, false},
+		{"missing key", "c", nil, false},
+	}
+	for _, c := range cases {
+		var got []int
+		found, err := reopened.Get("bucket", c.key, &got)
+		if err != nil {
+			t.Errorf("Get: %s; got error %v", c.name, err)
+		}
+		if found != c.found || !reflect.DeepEqual(got, c.want) {
+			t.Errorf("Get: %s; got %v, %v; want %v, %v", c.name, got, found, c.want, c.found)
+		}
+	}
+
+	keys, _ := reopened.Keys("bucket")
+	if !reflect.DeepEqual(keys, []string{"b"}) {
+		t.Errorf("Keys: got %v; want %v", keys, []string{"b"})
+	}
+}
+
+func TestWithPrefix(t *testing.T) {
+	s := NewMemory()
+	team, public := WithPrefix(s, "team"), WithPrefix(s, "public")
+
+	team.Put("settings", "1", "on")
+	public.Put("settings", "1", "off")
+	s.Put("archive", "7", "shared")
+
+	var got string
+	team.Get("settings", "1", &got)
+	if got != "on" {
+		t.Errorf("WithPrefix: got %q; want %q", got, "on")
+	}
+	if keys, _ := public.Keys("settings"); !reflect.DeepEqual(keys, []string{"1"}) {
+		t.Errorf("WithPrefix: Keys got %v", keys)
+	}
+	if ok, _ := team.Get("archive", "7", &got); ok {
+		t.Errorf("WithPrefix: got unprefixed bucket")
+	}
+	if WithPrefix(s, "") != Store(s) {
+		t.Errorf("WithPrefix: empty prefix; got a wrapper")
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store.json")
+	s, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open: got error %v", err)
+	}
+	s.Put("a", "1", "one")
+	s.Put("b", "2", []int{2})
+
+	data, err := s.Snapshot()
+	if err != nil {
+		t.Fatalf("Snapshot: got error %v", err)
+	}
+	s.Put("a", "3", "three")
+	if len(data["a"]) != 1 {
+		t.Errorf("Snapshot: got %d keys of a after Put; want 1", len(data["a"]))
+	}
+
+	if err = s.Restore(data); err != nil {
+		t.Fatalf("Restore: got error %v", err)
+	}
+	reopened, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open: got error %v", err)
+	}
+	var got string
+	if ok, _ := reopened.Get("a", "3", &got); ok {
+		t.Errorf("Restore: got key a/3 which is not in the snapshot")
+	}
+	if ok, _ := reopened.Get("a", "1", &got); !ok || got != "one" {
+		t.Errorf("Restore: got a/1 %q, %v; want one", got, ok)
+	}
+	if err = s.Restore(map[string]map[string]json.RawMessage{"a": {"1": json.RawMessage("{")}}); err == nil {
+		t.Errorf("Restore: invalid JSON; got no error")
+	}
+}
+
+func TestApply(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store.json")
+	s, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open: got error %v", err)
+	}
+	if err = s.Put("team/bucket", "old", 1); err != nil {
+		t.Fatalf("Put: got error %v", err)
+	}
+
+	b := &Batch{}
+	b.Put("bucket", "a", 2)
+	b.Put("bucket", "b", 3)
+	b.Delete("bucket", "old")
+	b.Put("bucket", "a", 4)
+	if err = Apply(WithPrefix(s, "team"), b); err != nil {
+		t.Fatalf("Apply: got error %v", err)
+	}
+
+	reopened, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open: got error %v", err)
+	}
+	keys, _ := reopened.Keys("team/bucket")
+	var a int
+	reopened.Get("team/bucket", "a", &a)
+	if !reflect.DeepEqual(keys, []string{"a", "b"}) || a != 4 {
+		t.Errorf("Apply: got keys %v and a %d; want [a b] and 4", keys, a)
+	}
+
+	bad := &Batch{}
+	bad.Delete("team/bucket", "a")
+	bad.Put("team/bucket", "c", make(chan int))
+	if err = Apply(s, bad); err == nil {
+		t.Errorf("Apply: got no error for a value which can't be encoded")
+	}
+	if keys, _ = s.Keys("team/bucket"); len(keys) != 2 {
+		t.Errorf("Apply: failed batch; got keys %v; want nothing changed", keys)
+	}
+}
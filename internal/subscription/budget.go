@@ -0,0 +1,98 @@
+package subscription
+
+import (
+	"sort"
+
+	"github.com/alebsys/telegram-article-bot/internal/devto"
+)
+
+// MaxPicks is the largest number of articles in a digest.
+const MaxPicks = 30
+
+// Pick selects articles of the digest: the first Limit ones, or those which fit
+// into the reading time budget if the subscription has one. A digest has at most
+// MaxPicks articles, the ones with the largest score are kept.
+func (s Subscription) Pick(articles devto.Articles) devto.Articles {
+	if s.Minutes > 0 {
+		return best(Budget(articles, s.Minutes), MaxPicks)
+	}
+	if s.Limit > MaxPicks {
+		return articles.Head(MaxPicks)
+	}
+	return articles.Head(s.Limit)
+}
+
+// best returns n articles with the largest score keeping their order.
+func best(articles devto.Articles, n int) devto.Articles {
+	if len(articles) <= n {
+		return articles
+	}
+	order := make([]int, len(articles))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return articles[order[i]].Score > articles[order[j]].Score
+	})
+	order = order[:n]
+	sort.Ints(order)
+
+	picked := make(devto.Articles, n)
+	for i, j := range order {
+		picked[i] = articles[j]
+	}
+	return picked
+}
+
+// Budget selects articles with the largest total score whose reading time fits into minutes,
+// keeping their order. Articles shorter than a minute take a minute.
+func Budget(articles devto.Articles, minutes int) devto.Articles {
+	if minutes <= 0 || len(articles) == 0 {
+		return nil
+	}
+	// best[i][m] is the largest score of the first i articles within m minutes
+	best := make([][]int, len(articles)+1)
+	best[0] = make([]int, minutes+1)
+	for i, a := range articles {
+		best[i+1] = make([]int, minutes+1)
+		w := readingMinutes(a)
+		for m := 0; m <= minutes; m++ {
+			best[i+1][m] = best[i][m]
+			if w <= m && best[i][m-w]+a.Score > best[i+1][m] {
+				best[i+1][m] = best[i][m-w] + a.Score
+			}
+		}
+	}
+
+	taken := make([]bool, len(articles))
+	for i, m := len(articles), minutes; i > 0; i-- {
+		if best[i][m] != best[i-1][m] {
+			taken[i-1] = true
+			m -= readingMinutes(articles[i-1])
+		}
+	}
+	var picked devto.Articles
+	for i, a := range articles {
+		if taken[i] {
+			picked = append(picked, a)
+		}
+	}
+	return picked
+}
+
+// ReadingTime returns the total reading time of articles in minutes, counted like in Budget.
+func ReadingTime(articles devto.Articles) int {
+	var total int
+	for _, a := range articles {
+		total += readingMinutes(a)
+	}
+	return total
+}
+
+// readingMinutes returns reading time of article, at least a minute.
+func readingMinutes(a devto.Article) int {
+	if a.ReadingTime < 1 {
+		return 1
+	}
+	return a.ReadingTime
+}
@@ -0,0 +1,106 @@
+package subscription
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/alebsys/telegram-article-bot/internal/store"
+)
+
+const bucket = "subscriptions"
+
+// Subscription is a set of tags a chat follows with a daily digest of their top articles.
+// Minutes is a reading time budget of the digest which replaces Limit unless it is zero.
+type Subscription struct {
+	ChatID   int64     `json:"chat_id"`
+	Tags     []string  `json:"tags"`
+	Limit    int       `json:"limit"`
+	Minutes  int       `json:"minutes,omitempty"`
+	Hour     int       `json:"hour"`
+	LastSent time.Time `json:"last_sent,omitempty"`
+}
+
+// Due reports whether the digest of the day has to be sent at now.
+func (s Subscription) Due(now time.Time) bool {
+	now = now.UTC()
+	if now.Hour() < s.Hour {
+		return false
+	}
+	y, m, d := s.LastSent.UTC().Date()
+	ny, nm, nd := now.Date()
+	return y != ny || m != nm || d != nd
+}
+
+// Subscriptions keeps subscriptions of chats.
+type Subscriptions struct {
+	mu    sync.Mutex
+	store store.Store
+}
+
+// New returns Subscriptions backed by s.
+func New(s store.Store) *Subscriptions {
+	return &Subscriptions{store: s}
+}
+
+// Get returns subscription of chat.
+func (s *Subscriptions) Get(chatID int64) (Subscription, bool, error) {
+	var sub Subscription
+	ok, err := s.store.Get(bucket, key(chatID), &sub)
+	if err != nil {
+		return sub, false, fmt.Errorf("error when reads subscription of %d: %v", chatID, err)
+	}
+	return sub, ok, nil
+}
+
+// Put saves subscription replacing the previous one of the chat.
+func (s *Subscriptions) Put(sub Subscription) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.store.Put(bucket, key(sub.ChatID), sub)
+}
+
+// Delete removes subscription of chat.
+func (s *Subscriptions) Delete(chatID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.store.Delete(bucket, key(chatID))
+}
+
+// All returns subscriptions of all chats.
+func (s *Subscriptions) All() ([]Subscription, error) {
+	keys, err := s.store.Keys(bucket)
+	if err != nil {
+		return nil, err
+	}
+	subs := make([]Subscription, 0, len(keys))
+	for _, k := range keys {
+		var sub Subscription
+		if _, err := s.store.Get(bucket, k, &sub); err != nil {
+			return nil, err
+		}
+		subs = append(subs, sub)
+	}
+	return subs, nil
+}
+
+// Export returns subscription of user private chat.
+func (s *Subscriptions) Export(userID int64) (interface{}, error) {
+	sub, ok, err := s.Get(userID)
+	if err != nil || !ok {
+		return nil, err
+	}
+	return sub, nil
+}
+
+// Forget removes subscription of user private chat.
+func (s *Subscriptions) Forget(userID int64) error {
+	return s.Delete(userID)
+}
+
+func key(chatID int64) string {
+	return strconv.FormatInt(chatID, 10)
+}
@@ -0,0 +1,86 @@
+package subscription
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/alebsys/telegram-article-bot/internal/devto"
+)
+
+func TestDue(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	cases := []struct {
+		name string
+		sub  Subscription
+		want bool
+	}{
+		{"never sent", Subscription{Hour: 9}, true},
+		{"too early", Subscription{Hour: 13}, false},
+		{"sent yesterday", Subscription{Hour: 9, LastSent: now.Add(-24 * time.Hour)}, true},
+		{"sent today", Subscription{Hour: 9, LastSent: now.Add(-time.Hour)}, false},
+	}
+	for _, c := range cases {
+		if got := c.sub.Due(now); got != c.want {
+			t.Errorf("Due: %s; got %v; want %v", c.name, got, c.want)
+		}
+	}
+}
+
+func TestBudget(t *testing.T) {
+	articles := devto.Articles{
+		{ID: 1, Score: 100, ReadingTime: 25},
+		{ID: 2, Score: 60, ReadingTime: 10},
+		{ID: 3, Score: 50, ReadingTime: 10},
+		{ID: 4, Score: 10, ReadingTime: 0},
+		{ID: 5, Score: 5, ReadingTime: 12},
+	}
+	cases := []struct {
+		name    string
+		minutes int
+		want    []int
+	}{
+		{"the longest one loses to two short ones", 21, []int{2, 3, 4}},
+		{"everything fits", 60, []int{1, 2, 3, 4, 5}},
+		{"best total score", 30, []int{2, 3, 4}},
+		{"one minute", 1, []int{4}},
+		{"no budget", 0, nil},
+	}
+	for _, c := range cases {
+		var got []int
+		for _, a := range Budget(articles, c.minutes) {
+			got = append(got, a.ID)
+		}
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("Budget: %s; got %v; want %v", c.name, got, c.want)
+		}
+	}
+
+	if got := ReadingTime(articles); got != 58 {
+		t.Errorf("ReadingTime: got %d; want 58 as an article shorter than a minute takes a minute", got)
+	}
+}
+
+func TestPick(t *testing.T) {
+	var articles devto.Articles
+	for i := 1; i <= 2*MaxPicks; i++ {
+		articles = append(articles, devto.Article{ID: i, Score: i % 7, ReadingTime: 0})
+	}
+
+	picked := Subscription{Minutes: 240}.Pick(articles)
+	if len(picked) != MaxPicks {
+		t.Fatalf("Pick: got %d articles; want %d", len(picked), MaxPicks)
+	}
+	for i, a := range picked {
+		if a.Score < 3 {
+			t.Errorf("Pick: got article %d with score %d; want the best ones", a.ID, a.Score)
+		}
+		if i > 0 && a.ID < picked[i-1].ID {
+			t.Errorf("Pick: got article %d after %d; want the order kept", a.ID, picked[i-1].ID)
+		}
+	}
+
+	if got := (Subscription{Limit: 5}).Pick(articles); len(got) != 5 || got[0].ID != 1 {
+		t.Errorf("Pick: limit 5; got %v", got)
+	}
+}
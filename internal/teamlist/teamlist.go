@@ -0,0 +1,282 @@
+package teamlist
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/alebsys/telegram-article-bot/internal/store"
+)
+
+const bucket = "teamlist"
+
+// Item is an article added to the team list.
+type Item struct {
+	ArticleID int       `json:"article_id"`
+	Title     string    `json:"title"`
+	URL       string    `json:"url"`
+	AddedBy   int64     `json:"added_by,omitempty"`
+	Added     time.Time `json:"added"`
+	Votes     []int64   `json:"votes,omitempty"`
+}
+
+// Pick is an item chosen by the reading club when the list was closed.
+type Pick struct {
+	Item
+	Week   string    `json:"week"`
+	Closed time.Time `json:"closed"`
+}
+
+// List is a reading list shared by a group chat.
+type List struct {
+	Items []Item `json:"items"`
+	Picks []Pick `json:"picks,omitempty"`
+}
+
+// TeamLists keeps reading lists of group chats.
+type TeamLists struct {
+	mu    sync.Mutex
+	store store.Store
+}
+
+// New returns TeamLists backed by s.
+func New(s store.Store) *TeamLists {
+	return &TeamLists{store: s}
+}
+
+// Add adds item to the list of chat and reports false if it is already there.
+func (t *TeamLists) Add(chatID int64, item Item) (bool, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	list, err := t.get(chatID)
+	if err != nil {
+		return false, err
+	}
+	if list.find(item.ArticleID) >= 0 {
+		return false, nil
+	}
+	list.Items = append(list.Items, item)
+	return true, t.put(chatID, list)
+}
+
+// Vote toggles vote of user for article and reports whether the vote is set now.
+func (t *TeamLists) Vote(chatID int64, articleID int, userID int64) (bool, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	list, err := t.get(chatID)
+	if err != nil {
+		return false, err
+	}
+	i := list.find(articleID)
+	if i < 0 {
+		return false, fmt.Errorf("article %d is not in the list of chat %d", articleID, chatID)
+	}
+
+	item := &list.Items[i]
+	voted := !item.voted(userID)
+	if voted {
+		item.Votes = append(item.Votes, userID)
+	} else {
+		item.Votes = removeUser(item.Votes, userID)
+	}
+	return voted, t.put(chatID, list)
+}
+
+// Ranked returns items of chat list sorted by votes, earlier added items go first on a tie.
+func (t *TeamLists) Ranked(chatID int64) ([]Item, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	list, err := t.get(chatID)
+	if err != nil {
+		return nil, err
+	}
+	return list.ranked(), nil
+}
+
+// Close picks the most voted item as the reading club pick of the week and clears the list.
+func (t *TeamLists) Close(chatID int64, now time.Time) (Pick, bool, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	list, err := t.get(chatID)
+	if err != nil {
+		return Pick{}, false, err
+	}
+	ranked := list.ranked()
+	if len(ranked) == 0 {
+		return Pick{}, false, nil
+	}
+
+	year, week := now.ISOWeek()
+	pick := Pick{Item: ranked[0], Week: fmt.Sprintf("%d-W%02d", year, week), Closed: now}
+	list.Picks = append(list.Picks, pick)
+	list.Items = nil
+	return pick, true, t.put(chatID, list)
+}
+
+// Picks returns reading club picks of chat from the oldest to the newest.
+func (t *TeamLists) Picks(chatID int64) ([]Pick, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	list, err := t.get(chatID)
+	if err != nil {
+		return nil, err
+	}
+	return list.Picks, nil
+}
+
+// Export returns items and picks added or voted by user in every chat.
+func (t *TeamLists) Export(userID int64) (interface{}, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	type chatItems struct {
+		ChatID int64  `json:"chat_id"`
+		Added  []Item `json:"added,omitempty"`
+		Voted  []Item `json:"voted,omitempty"`
+		Picks  []Pick `json:"picks,omitempty"`
+	}
+	var export []chatItems
+
+	err := t.each(func(chatID int64, list *List) (bool, error) {
+		c := chatItems{ChatID: chatID}
+		for _, item := range list.Items {
+			if item.AddedBy == userID {
+				c.Added = append(c.Added, item)
+			}
+			if item.voted(userID) {
+				c.Voted = append(c.Voted, item)
+			}
+		}
+		for _, pick := range list.Picks {
+			if pick.AddedBy == userID || pick.voted(userID) {
+				c.Picks = append(c.Picks, pick)
+			}
+		}
+		if len(c.Added) > 0 || len(c.Voted) > 0 || len(c.Picks) > 0 {
+			export = append(export, c)
+		}
+		return false, nil
+	})
+	return export, err
+}
+
+// Forget removes votes of user and the user as an author of items in every chat.
+func (t *TeamLists) Forget(userID int64) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return t.each(func(chatID int64, list *List) (bool, error) {
+		changed := false
+		for i := range list.Items {
+			item := &list.Items[i]
+			if item.AddedBy == userID {
+				item.AddedBy = 0
+				changed = true
+			}
+			if item.voted(userID) {
+				item.Votes = removeUser(item.Votes, userID)
+				changed = true
+			}
+		}
+		for i := range list.Picks {
+			pick := &list.Picks[i]
+			if pick.AddedBy == userID {
+				pick.AddedBy = 0
+				changed = true
+			}
+			if pick.voted(userID) {
+				pick.Votes = removeUser(pick.Votes, userID)
+				changed = true
+			}
+		}
+		return changed, nil
+	})
+}
+
+// each calls fn for list of every chat and saves the list if fn reports it was changed.
+func (t *TeamLists) each(fn func(chatID int64, list *List) (bool, error)) error {
+	keys, err := t.store.Keys(bucket)
+	if err != nil {
+		return err
+	}
+	for _, k := range keys {
+		chatID, err := strconv.ParseInt(k, 10, 64)
+		if err != nil {
+			continue
+		}
+		list, err := t.get(chatID)
+		if err != nil {
+			return err
+		}
+		changed, err := fn(chatID, list)
+		if err != nil {
+			return err
+		}
+		if changed {
+			if err = t.put(chatID, list); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (t *TeamLists) get(chatID int64) (*List, error) {
+	list := new(List)
+	if _, err := t.store.Get(bucket, key(chatID), list); err != nil {
+		return nil, fmt.Errorf("error when reads team list of %d: %v", chatID, err)
+	}
+	return list, nil
+}
+
+func (t *TeamLists) put(chatID int64, list *List) error {
+	return t.store.Put(bucket, key(chatID), list)
+}
+
+func (l *List) find(articleID int) int {
+	for i, item := range l.Items {
+		if item.ArticleID == articleID {
+			return i
+		}
+	}
+	return -1
+}
+
+func (l *List) ranked() []Item {
+	items := make([]Item, len(l.Items))
+	copy(items, l.Items)
+	sort.SliceStable(items, func(i, j int) bool {
+		return len(items[i].Votes) > len(items[j].Votes)
+	})
+	return items
+}
+
+func (i *Item) voted(userID int64) bool {
+	for _, id := range i.Votes {
+		if id == userID {
+			return true
+		}
+	}
+	return false
+}
+
+func removeUser(users []int64, userID int64) []int64 {
+	kept := users[:0]
+	for _, id := range users {
+		if id != userID {
+			kept = append(kept, id)
+		}
+	}
+	return kept
+}
+
+func key(chatID int64) string {
+	return strconv.FormatInt(chatID, 10)
+}
@@ -0,0 +1,98 @@
+package teamlist
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alebsys/telegram-article-bot/internal/store"
+)
+
+func TestVoteAndClose(t *testing.T) {
+	lists := New(store.NewMemory())
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	lists.Add(1, Item{ArticleID: 10, Title: "first"})
+	lists.Add(1, Item{ArticleID: 20, Title: "second"})
+	lists.Add(1, Item{ArticleID: 30, Title: "third"})
+
+	if added, _ := lists.Add(1, Item{ArticleID: 10}); added {
+		t.Errorf("Add: duplicate article added")
+	}
+
+	votes := []struct {
+		articleID int
+		userID    int64
+		want      bool
+	}{
+		{20, 100, true},
+		{20, 200, true},
+		{30, 100, true},
+		{30, 100, false},
+	}
+	for _, v := range votes {
+		got, err := lists.Vote(1, v.articleID, v.userID)
+		if err != nil {
+			t.Fatalf("Vote: got error %v", err)
+		}
+		if got != v.want {
+			t.Errorf("Vote: article %d by %d; got %v; want %v", v.articleID, v.userID, got, v.want)
+		}
+	}
+	if _, err := lists.Vote(1, 40, 100); err == nil {
+		t.Errorf("Vote: missing article; got no error")
+	}
+
+	ranked, _ := lists.Ranked(1)
+	want := []int{20, 10, 30}
+	for i, item := range ranked {
+		if item.ArticleID != want[i] {
+			t.Errorf("Ranked: position %d; got %d; want %d", i, item.ArticleID, want[i])
+		}
+	}
+
+	pick, ok, err := lists.Close(1, now)
+	if err != nil || !ok {
+		t.Fatalf("Close: got %v, %v", ok, err)
+	}
+	if pick.ArticleID != 20 || pick.Week != "2026-W42" {
+		t.Errorf("Close: got %d, %s; want 20, 2026-W42", pick.ArticleID, pick.Week)
+	}
+	if items, _ := lists.Ranked(1); len(items) != 0 {
+		t.Errorf("Close: list is not cleared, got %v", items)
+	}
+	if _, ok, _ = lists.Close(1, now); ok {
+		t.Errorf("Close: empty list closed")
+	}
+}
+
+func TestForget(t *testing.T) {
+	lists := New(store.NewMemory())
+	lists.Add(1, Item{ArticleID: 5, AddedBy: 100})
+	lists.Vote(1, 5, 200)
+	lists.Close(1, time.Now())
+	lists.Add(1, Item{ArticleID: 10, AddedBy: 100})
+	lists.Vote(1, 10, 100)
+	lists.Vote(1, 10, 200)
+
+	export, _ := lists.Export(200)
+	want := `[{"chat_id":1,"voted":[{"article_id":10`
+	if data, _ := json.Marshal(export); !strings.HasPrefix(string(data), want) || !strings.Contains(string(data), `"picks":[{"article_id":5`) {
+		t.Errorf("Export: got %s; want voted items and picks", data)
+	}
+
+	if err := lists.Forget(100); err != nil {
+		t.Fatalf("Forget: got error %v", err)
+	}
+	items, _ := lists.Ranked(1)
+	if items[0].AddedBy != 0 || len(items[0].Votes) != 1 || items[0].Votes[0] != 200 {
+		t.Errorf("Forget: got %+v", items[0])
+	}
+	if picks, _ := lists.Picks(1); picks[0].AddedBy != 0 {
+		t.Errorf("Forget: got pick %+v", picks[0])
+	}
+	if export, _ := lists.Export(100); reflect.ValueOf(export).Len() != 0 {
+		t.Errorf("Export: got %v after Forget", export)
+	}
+}